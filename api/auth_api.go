@@ -0,0 +1,75 @@
+package api
+
+import (
+	"strings"
+
+	"datatrace/common"
+	"datatrace/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthAPI 认证API控制器
+type AuthAPI struct {
+	service *services.AuthService
+}
+
+// NewAuthAPI 创建认证API控制器
+func NewAuthAPI() *AuthAPI {
+	return &AuthAPI{service: services.NewAuthService()}
+}
+
+// Login 用户登录，返回access/refresh token
+func (api *AuthAPI) Login(c *gin.Context) {
+	var req services.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	resp, err := api.service.Login(&req)
+	if err != nil {
+		common.Unauthorized(c, err.Error())
+		return
+	}
+
+	common.Success(c, resp)
+}
+
+// RefreshRequest 刷新token请求
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Refresh 使用refresh token换取新的access token
+func (api *AuthAPI) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	resp, err := api.service.Refresh(req.RefreshToken)
+	if err != nil {
+		common.Unauthorized(c, err.Error())
+		return
+	}
+
+	common.Success(c, resp)
+}
+
+// Logout 登出，将当前access token加入黑名单
+func (api *AuthAPI) Logout(c *gin.Context) {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" {
+		common.BadRequest(c, "缺少认证token")
+		return
+	}
+
+	if err := api.service.Logout(token); err != nil {
+		common.Error(c, 500, err.Error())
+		return
+	}
+
+	common.SuccessWithMessage(c, "登出成功", nil)
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"datatrace/common"
+	"datatrace/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CryptoAPI 信封加密密钥管理API控制器
+type CryptoAPI struct {
+	crypto *utils.CryptoService
+}
+
+// NewCryptoAPI 创建密钥管理API控制器
+func NewCryptoAPI() *CryptoAPI {
+	return &CryptoAPI{crypto: utils.NewCryptoService()}
+}
+
+// Rotate 立即生成新一代RSA密钥并设为激活版本，旧版本保留用于解密历史数据
+func (api *CryptoAPI) Rotate(c *gin.Context) {
+	if err := api.crypto.RotateKey(); err != nil {
+		common.Error(c, 500, err.Error())
+		return
+	}
+
+	common.SuccessWithMessage(c, "密钥轮换成功", nil)
+}
+
+// ListKeys 列出全部密钥版本的脱敏信息（版本号/是否激活/生成节点/创建时间），不返回密钥材料
+func (api *CryptoAPI) ListKeys(c *gin.Context) {
+	keys, err := api.crypto.ListKeyVersions()
+	if err != nil {
+		common.Error(c, 500, err.Error())
+		return
+	}
+
+	common.Success(c, keys)
+}
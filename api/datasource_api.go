@@ -2,21 +2,50 @@ package api
 
 import (
 	"datatrace/common"
+	"datatrace/models"
 	"datatrace/services"
 	"datatrace/utils"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 // DataSourceAPI 数据源API控制器
 type DataSourceAPI struct {
 	service *services.DataSourceService
+	audit   *services.AuditService
 }
 
 // NewDataSourceAPI 创建数据源API控制器
 func NewDataSourceAPI() *DataSourceAPI {
 	return &DataSourceAPI{
 		service: services.NewDataSourceService(),
+		audit:   services.NewAuditService(),
+	}
+}
+
+// recordAudit 以当前登录用户为operator写入一条审计日志；写入失败只打印警告，不影响主流程的响应
+func (api *DataSourceAPI) recordAudit(c *gin.Context, action, targetID string, before, after *models.DataSource) {
+	actor := services.AuditActor{UserID: common.CurrentUserID(c), Username: common.CurrentUsername(c)}
+	if err := api.audit.Record(actor, action, "datasource", targetID, auditSnapshot(before), auditSnapshot(after), common.RequestID(c), c.ClientIP()); err != nil {
+		utils.Log.Warn("写入审计日志失败", zap.String("action", action), zap.String("datasource_id", targetID), zap.Error(err))
+	}
+}
+
+// auditSnapshot 构造数据源的审计快照，屏蔽信封加密存储的密码密文，避免密文随审计日志一起扩散
+func auditSnapshot(ds *models.DataSource) map[string]interface{} {
+	if ds == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"id":            ds.ID,
+		"name":          ds.Name,
+		"type":          ds.Type,
+		"host":          ds.Host,
+		"port":          ds.Port,
+		"username":      ds.Username,
+		"database_name": ds.DatabaseName,
+		"use_ssl":       ds.UseSSL,
 	}
 }
 
@@ -34,6 +63,7 @@ func (api *DataSourceAPI) Create(c *gin.Context) {
 		return
 	}
 
+	api.recordAudit(c, "datasource.create", ds.ID, nil, ds)
 	common.Success(c, ds)
 }
 
@@ -69,23 +99,46 @@ func (api *DataSourceAPI) Update(c *gin.Context) {
 		return
 	}
 
+	before, err := api.service.GetByID(id)
+	if err != nil {
+		common.NotFound(c, "数据源不存在")
+		return
+	}
+
+	// 连接凭证（host/port/username/password）属于加密相关字段，只有admin可以修改，
+	// operator只能改名称等非凭证字段
+	credentialsChanged := req.Host != before.Host || req.Port != before.Port ||
+		req.Username != before.Username || req.Password != ""
+	if credentialsChanged && common.CurrentRole(c) != "admin" {
+		common.Forbidden(c, "仅admin可修改数据源的连接凭证")
+		return
+	}
+
 	ds, err := api.service.Update(id, &req)
 	if err != nil {
 		common.BadRequest(c, err.Error())
 		return
 	}
 
+	api.recordAudit(c, "datasource.update", id, before, ds)
 	common.Success(c, ds)
 }
 
 // Delete 删除数据源
 func (api *DataSourceAPI) Delete(c *gin.Context) {
 	id := c.Param("id")
+	before, err := api.service.GetByID(id)
+	if err != nil {
+		common.NotFound(c, "数据源不存在")
+		return
+	}
+
 	if err := api.service.Delete(id); err != nil {
 		common.BadRequest(c, err.Error())
 		return
 	}
 
+	api.recordAudit(c, "datasource.delete", id, before, nil)
 	common.SuccessWithMessage(c, "删除成功", nil)
 }
 
@@ -127,7 +180,43 @@ func (api *DataSourceAPI) TestConnectionByID(c *gin.Context) {
 	}
 }
 
-// GetDatabases 获取数据源的数据库列表（仅MySQL）
+// PoolStats 获取数据源连接池的统计信息（db.Stats()），用于观测连接池使用情况
+func (api *DataSourceAPI) PoolStats(c *gin.Context) {
+	id := c.Param("id")
+
+	stats, found, err := api.service.PoolStats(id)
+	if err != nil {
+		common.NotFound(c, err.Error())
+		return
+	}
+	if !found {
+		common.SuccessWithMessage(c, "该数据源尚未建立连接池或非MySQL类型", nil)
+		return
+	}
+
+	common.Success(c, stats)
+}
+
+// Preview 对数据源执行一次只读的即席SQL/DSL查询，返回样例数据
+func (api *DataSourceAPI) Preview(c *gin.Context) {
+	id := c.Param("id")
+
+	var req services.PreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := api.service.Preview(id, &req)
+	if err != nil {
+		common.BadRequest(c, err.Error())
+		return
+	}
+
+	common.Success(c, result)
+}
+
+// GetDatabases 获取数据源的数据库列表
 func (api *DataSourceAPI) GetDatabases(c *gin.Context) {
 	id := c.Param("id")
 
@@ -138,8 +227,9 @@ func (api *DataSourceAPI) GetDatabases(c *gin.Context) {
 		return
 	}
 
-	if ds.Type != "mysql" {
-		common.BadRequest(c, "只有MySQL数据源支持此操作")
+	metaService, err := services.NewMetadataProvider(ds.Type)
+	if err != nil {
+		common.BadRequest(c, err.Error())
 		return
 	}
 
@@ -152,8 +242,7 @@ func (api *DataSourceAPI) GetDatabases(c *gin.Context) {
 	}
 
 	// 获取数据库列表
-	mysqlService := services.NewMySQLMetadataService()
-	databases, err := mysqlService.GetDatabases(ds.Host, ds.Port, ds.Username, password)
+	databases, err := metaService.GetDatabases(ds.Host, ds.Port, ds.Username, password)
 	if err != nil {
 		common.Error(c, 500, err.Error())
 		return
@@ -162,7 +251,7 @@ func (api *DataSourceAPI) GetDatabases(c *gin.Context) {
 	common.Success(c, databases)
 }
 
-// GetTables 获取指定数据库的表列表（仅MySQL）
+// GetTables 获取指定数据库的表列表
 func (api *DataSourceAPI) GetTables(c *gin.Context) {
 	id := c.Param("id")
 	database := c.Query("database")
@@ -179,8 +268,9 @@ func (api *DataSourceAPI) GetTables(c *gin.Context) {
 		return
 	}
 
-	if ds.Type != "mysql" {
-		common.BadRequest(c, "只有MySQL数据源支持此操作")
+	metaService, err := services.NewMetadataProvider(ds.Type)
+	if err != nil {
+		common.BadRequest(c, err.Error())
 		return
 	}
 
@@ -193,8 +283,7 @@ func (api *DataSourceAPI) GetTables(c *gin.Context) {
 	}
 
 	// 获取表列表
-	mysqlService := services.NewMySQLMetadataService()
-	tables, err := mysqlService.GetTables(ds.Host, ds.Port, ds.Username, password, database)
+	tables, err := metaService.GetTables(ds.Host, ds.Port, ds.Username, password, database)
 	if err != nil {
 		common.Error(c, 500, err.Error())
 		return
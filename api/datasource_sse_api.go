@@ -1,7 +1,10 @@
 package api
 
 import (
+	"datatrace/common"
 	"datatrace/services"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 
@@ -11,12 +14,14 @@ import (
 // DataSourceSSEAPI 数据源SSE API控制器
 type DataSourceSSEAPI struct {
 	sseService *services.DataSourceSSEService
+	dsService  *services.DataSourceService
 }
 
 // NewDataSourceSSEAPI 创建数据源SSE API控制器
 func NewDataSourceSSEAPI() *DataSourceSSEAPI {
 	return &DataSourceSSEAPI{
 		sseService: services.NewDataSourceSSEService(),
+		dsService:  services.NewDataSourceService(),
 	}
 }
 
@@ -69,3 +74,56 @@ func (api *DataSourceSSEAPI) StreamTestResults(c *gin.Context) {
 		}
 	}
 }
+
+// StreamQueryResults 对数据源执行一次结构化的即席查询，把样例数据按行以SSE推送给客户端，
+// 避免大结果集整批缓冲在内存里；请求体通过?q=<JSON>查询参数传递（EventSource无法设置请求体/请求头），
+// 客户端断开时c.Request.Context()被取消，底层db.QueryContext随之中止
+func (api *DataSourceSSEAPI) StreamQueryResults(c *gin.Context) {
+	id := c.Param("id")
+
+	var req services.PreviewRequest
+	if q := c.Query("q"); q != "" {
+		if err := json.Unmarshal([]byte(q), &req); err != nil {
+			common.BadRequest(c, "参数错误: "+err.Error())
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("Access-Control-Allow-Origin", "*")
+
+	w := c.Writer
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		c.String(http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	rowCh := make(chan map[string]interface{}, 10)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(rowCh)
+		_, err := api.dsService.PreviewStream(c.Request.Context(), id, &req, rowCh)
+		errCh <- err
+	}()
+
+	for row := range rowCh {
+		data, err := json.Marshal(row)
+		if err != nil {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "event: row\ndata: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+
+	if err := <-errCh; err != nil {
+		fmt.Fprintf(w, "event: error\ndata: %s\n\n", err.Error())
+	} else {
+		io.WriteString(w, "event: done\ndata: {}\n\n")
+	}
+	flusher.Flush()
+}
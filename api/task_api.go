@@ -3,19 +3,31 @@ package api
 import (
 	"datatrace/common"
 	"datatrace/services"
+	"datatrace/utils"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 // TaskAPI 任务API控制器
 type TaskAPI struct {
 	service *services.TaskService
+	audit   *services.AuditService
 }
 
 // NewTaskAPI 创建任务API控制器
 func NewTaskAPI() *TaskAPI {
 	return &TaskAPI{
 		service: services.NewTaskService(),
+		audit:   services.NewAuditService(),
+	}
+}
+
+// recordAudit 以当前登录用户为operator写入一条审计日志；写入失败只打印警告，不影响主流程的响应
+func (api *TaskAPI) recordAudit(c *gin.Context, action, taskID string) {
+	actor := services.AuditActor{UserID: common.CurrentUserID(c), Username: common.CurrentUsername(c)}
+	if err := api.audit.Record(actor, action, "task", taskID, nil, nil, common.RequestID(c), c.ClientIP()); err != nil {
+		utils.Log.Warn("写入审计日志失败", zap.String("action", action), zap.String("task_id", taskID), zap.Error(err))
 	}
 }
 
@@ -85,5 +97,6 @@ func (api *TaskAPI) Delete(c *gin.Context) {
 		return
 	}
 
+	api.recordAudit(c, "task.delete", id)
 	common.SuccessWithMessage(c, "删除成功", nil)
 }
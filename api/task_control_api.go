@@ -3,19 +3,31 @@ package api
 import (
 	"datatrace/common"
 	"datatrace/services"
+	"datatrace/utils"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 // TaskControlAPI 任务控制API控制器
 type TaskControlAPI struct {
 	service *services.TaskControlService
+	audit   *services.AuditService
 }
 
 // NewTaskControlAPI 创建任务控制API控制器
 func NewTaskControlAPI() *TaskControlAPI {
 	return &TaskControlAPI{
 		service: services.NewTaskControlService(),
+		audit:   services.NewAuditService(),
+	}
+}
+
+// recordAudit 以当前登录用户为operator写入一条审计日志；写入失败只打印警告，不影响主流程的响应
+func (api *TaskControlAPI) recordAudit(c *gin.Context, action, taskID string) {
+	actor := services.AuditActor{UserID: common.CurrentUserID(c), Username: common.CurrentUsername(c)}
+	if err := api.audit.Record(actor, action, "task", taskID, nil, nil, common.RequestID(c), c.ClientIP()); err != nil {
+		utils.Log.Warn("写入审计日志失败", zap.String("action", action), zap.String("task_id", taskID), zap.Error(err))
 	}
 }
 
@@ -28,6 +40,7 @@ func (api *TaskControlAPI) Start(c *gin.Context) {
 		return
 	}
 
+	api.recordAudit(c, "task.start", taskID)
 	common.SuccessWithMessage(c, "任务启动成功", nil)
 }
 
@@ -40,6 +53,7 @@ func (api *TaskControlAPI) Pause(c *gin.Context) {
 		return
 	}
 
+	api.recordAudit(c, "task.pause", taskID)
 	common.SuccessWithMessage(c, "任务暂停成功", nil)
 }
 
@@ -52,5 +66,85 @@ func (api *TaskControlAPI) Stop(c *gin.Context) {
 		return
 	}
 
+	api.recordAudit(c, "task.stop", taskID)
 	common.SuccessWithMessage(c, "任务停止成功", nil)
 }
+
+// ResetCheckpoint 重置指定任务单元的续传断点，使其下次启动时从头同步
+func (api *TaskControlAPI) ResetCheckpoint(c *gin.Context) {
+	taskID := c.Param("id")
+	unitName := c.Param("unit")
+
+	if err := api.service.ResetCheckpoint(taskID, unitName); err != nil {
+		common.BadRequest(c, err.Error())
+		return
+	}
+
+	common.SuccessWithMessage(c, "断点重置成功", nil)
+}
+
+// InternalPause 供集群内其他节点转发的Pause请求，/internal路由用共享密钥（RequireInternalSecret）
+// 代替JWT鉴权，只应该被ClusterService.ForwardControl调用
+func (api *TaskControlAPI) InternalPause(c *gin.Context) {
+	taskID := c.Param("id")
+
+	if err := api.service.PauseTask(taskID); err != nil {
+		common.BadRequest(c, err.Error())
+		return
+	}
+
+	api.recordAudit(c, "task.pause", taskID)
+	common.SuccessWithMessage(c, "任务暂停成功", nil)
+}
+
+// InternalStop 供集群内其他节点转发的Stop请求，/internal路由用共享密钥代替JWT鉴权
+func (api *TaskControlAPI) InternalStop(c *gin.Context) {
+	taskID := c.Param("id")
+
+	if err := api.service.StopTask(taskID); err != nil {
+		common.BadRequest(c, err.Error())
+		return
+	}
+
+	api.recordAudit(c, "task.stop", taskID)
+	common.SuccessWithMessage(c, "任务停止成功", nil)
+}
+
+// ReprioritizeUnit 单独调整某个任务单元的优先级，不影响同任务内的其它单元
+func (api *TaskControlAPI) ReprioritizeUnit(c *gin.Context) {
+	taskID := c.Param("id")
+	unitName := c.Param("unit")
+
+	var req services.UpdateTaskPriorityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := api.service.ReprioritizeUnit(taskID, unitName, req.Priority); err != nil {
+		common.BadRequest(c, err.Error())
+		return
+	}
+
+	api.recordAudit(c, "task.unit.reprioritize", taskID)
+	common.SuccessWithMessage(c, "单元优先级更新成功", nil)
+}
+
+// Reprioritize 调整任务优先级（可在任务运行中调用，立即影响尚未开始的单元）
+func (api *TaskControlAPI) Reprioritize(c *gin.Context) {
+	taskID := c.Param("id")
+
+	var req services.UpdateTaskPriorityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := api.service.Reprioritize(taskID, req.Priority, req.EndTime); err != nil {
+		common.BadRequest(c, err.Error())
+		return
+	}
+
+	api.recordAudit(c, "task.reprioritize", taskID)
+	common.SuccessWithMessage(c, "优先级更新成功", nil)
+}
@@ -4,6 +4,7 @@ import (
 	"datatrace/common"
 	"datatrace/services"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -35,7 +36,7 @@ func (api *TaskMonitorAPI) GetProgress(c *gin.Context) {
 	common.Success(c, progress)
 }
 
-// GetLogs 获取任务日志
+// GetLogs 获取任务日志，支持level/category/since/until/search过滤及limit/offset分页
 func (api *TaskMonitorAPI) GetLogs(c *gin.Context) {
 	taskID := c.Param("id")
 
@@ -47,7 +48,22 @@ func (api *TaskMonitorAPI) GetLogs(c *gin.Context) {
 		}
 	}
 
-	logs, err := api.logService.GetTaskLogs(taskID, limit)
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o > 0 {
+			offset = o
+		}
+	}
+
+	var since, until time.Time
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		since, _ = time.Parse(time.RFC3339, sinceStr)
+	}
+	if untilStr := c.Query("until"); untilStr != "" {
+		until, _ = time.Parse(time.RFC3339, untilStr)
+	}
+
+	logs, err := api.logService.GetTaskLogs(taskID, c.Query("level"), c.Query("category"), since, until, c.Query("search"), limit, offset)
 	if err != nil {
 		common.Error(c, 500, err.Error())
 		return
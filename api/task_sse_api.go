@@ -4,6 +4,8 @@ import (
 	"datatrace/services"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,7 +22,7 @@ func NewTaskSSEAPI() *TaskSSEAPI {
 	}
 }
 
-// StreamTaskUpdates 流式推送任务更新
+// StreamTaskUpdates 流式推送任务更新，支持通过Last-Event-ID请求头断线重放
 func (api *TaskSSEAPI) StreamTaskUpdates(c *gin.Context) {
 	taskID := c.Param("id")
 
@@ -30,12 +32,13 @@ func (api *TaskSSEAPI) StreamTaskUpdates(c *gin.Context) {
 	c.Header("Connection", "keep-alive")
 	c.Header("Access-Control-Allow-Origin", "*")
 
-	// 创建客户端通道
-	client := make(chan services.SSEMessage, 10)
-
-	// 添加客户端
-	api.sseService.AddClient(taskID, client)
-	defer api.sseService.RemoveClient(taskID, client)
+	// 浏览器EventSource断线重连时会自动带上该请求头
+	var lastEventID int64
+	if idStr := c.GetHeader("Last-Event-ID"); idStr != "" {
+		if id, err := strconv.ParseInt(idStr, 10, 64); err == nil {
+			lastEventID = id
+		}
+	}
 
 	// 获取响应写入器
 	w := c.Writer
@@ -50,7 +53,8 @@ func (api *TaskSSEAPI) StreamTaskUpdates(c *gin.Context) {
 	defer close(done)
 
 	// 启动推送协程
-	go api.sseService.StreamTaskUpdates(taskID, client, done)
+	out := make(chan services.SSEMessage, 32)
+	go api.sseService.StreamTaskUpdates(taskID, lastEventID, out, done)
 
 	// 监听客户端断开
 	notify := c.Request.Context().Done()
@@ -61,7 +65,7 @@ func (api *TaskSSEAPI) StreamTaskUpdates(c *gin.Context) {
 		case <-notify:
 			// 客户端断开连接
 			return
-		case msg, ok := <-client:
+		case msg, ok := <-out:
 			if !ok {
 				// 通道关闭
 				return
@@ -75,3 +79,172 @@ func (api *TaskSSEAPI) StreamTaskUpdates(c *gin.Context) {
 		}
 	}
 }
+
+// StreamEvents 在一条SSE连接上同时订阅?task_ids=a,b,c指定的多个任务，供看板类页面使用，
+// 避免每个任务都单独开一条连接
+func (api *TaskSSEAPI) StreamEvents(c *gin.Context) {
+	taskIDsParam := c.Query("task_ids")
+	var taskIDs []string
+	for _, id := range strings.Split(taskIDsParam, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			taskIDs = append(taskIDs, id)
+		}
+	}
+	if len(taskIDs) == 0 {
+		c.String(http.StatusBadRequest, "task_ids不能为空")
+		return
+	}
+
+	// 设置SSE响应头
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("Access-Control-Allow-Origin", "*")
+
+	lastEventIDs := parseMultiplexLastEventID(c.GetHeader("Last-Event-ID"))
+
+	w := c.Writer
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		c.String(http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	out := make(chan services.SSEMessage, 32*len(taskIDs))
+	go api.sseService.StreamMultiTaskUpdates(taskIDs, lastEventIDs, out, done)
+
+	notify := c.Request.Context().Done()
+
+	for {
+		select {
+		case <-notify:
+			return
+		case msg, ok := <-out:
+			if !ok {
+				return
+			}
+			_, err := io.WriteString(w, services.FormatSSEMessage(msg))
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// parseMultiplexLastEventID 解析多路复用场景下的Last-Event-ID请求头，
+// 格式为"taskA:12,taskB:34"，每个任务各自携带上次收到的事件序号
+func parseMultiplexLastEventID(header string) map[string]int64 {
+	ids := make(map[string]int64)
+	if header == "" {
+		return ids
+	}
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if id, err := strconv.ParseInt(kv[1], 10, 64); err == nil {
+			ids[kv[0]] = id
+		}
+	}
+	return ids
+}
+
+// StreamTaskProgress 只流式推送任务的进度事件（不含日志/阶段事件），供仪表盘用单独一条瘦连接
+// 替代原先对GetTaskProgress的轮询；底层仍复用TaskEventBus，Worker端的高频更新已由ProgressBroker合并
+func (api *TaskSSEAPI) StreamTaskProgress(c *gin.Context) {
+	taskID := c.Param("id")
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("Access-Control-Allow-Origin", "*")
+
+	var lastEventID int64
+	if idStr := c.GetHeader("Last-Event-ID"); idStr != "" {
+		if id, err := strconv.ParseInt(idStr, 10, 64); err == nil {
+			lastEventID = id
+		}
+	}
+
+	w := c.Writer
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		c.String(http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	all := make(chan services.SSEMessage, 32)
+	go api.sseService.StreamTaskUpdates(taskID, lastEventID, all, done)
+
+	notify := c.Request.Context().Done()
+
+	for {
+		select {
+		case <-notify:
+			return
+		case msg, ok := <-all:
+			if !ok {
+				return
+			}
+			if msg.Event != "progress" && msg.Event != "heartbeat" && msg.Event != "overflow" {
+				continue
+			}
+			if _, err := io.WriteString(w, services.FormatSSEMessage(msg)); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// StreamTaskLogs 流式推送任务日志，支持通过level/category查询参数做服务端过滤
+func (api *TaskSSEAPI) StreamTaskLogs(c *gin.Context) {
+	taskID := c.Param("id")
+	level := c.Query("level")
+	category := c.Query("category")
+
+	// 设置SSE响应头
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("Access-Control-Allow-Origin", "*")
+
+	w := c.Writer
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		c.String(http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	out := make(chan services.SSEMessage, 32)
+	go api.sseService.StreamTaskLogs(taskID, level, category, out, done)
+
+	notify := c.Request.Context().Done()
+
+	for {
+		select {
+		case <-notify:
+			return
+		case msg, ok := <-out:
+			if !ok {
+				return
+			}
+			_, err := io.WriteString(w, services.FormatSSEMessage(msg))
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
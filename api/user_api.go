@@ -0,0 +1,57 @@
+package api
+
+import (
+	"datatrace/common"
+	"datatrace/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserAPI 用户管理API控制器
+type UserAPI struct {
+	service *services.UserService
+}
+
+// NewUserAPI 创建用户管理API控制器
+func NewUserAPI() *UserAPI {
+	return &UserAPI{service: services.NewUserService()}
+}
+
+// List 获取用户列表
+func (api *UserAPI) List(c *gin.Context) {
+	users, err := api.service.List()
+	if err != nil {
+		common.Error(c, 500, err.Error())
+		return
+	}
+
+	common.Success(c, users)
+}
+
+// Create 创建用户
+func (api *UserAPI) Create(c *gin.Context) {
+	var req services.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	user, err := api.service.Create(&req)
+	if err != nil {
+		common.BadRequest(c, err.Error())
+		return
+	}
+
+	common.Success(c, user)
+}
+
+// Delete 删除用户
+func (api *UserAPI) Delete(c *gin.Context) {
+	id := c.Param("id")
+	if err := api.service.Delete(id); err != nil {
+		common.BadRequest(c, err.Error())
+		return
+	}
+
+	common.SuccessWithMessage(c, "删除成功", nil)
+}
@@ -0,0 +1,114 @@
+package common
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"datatrace/config"
+	"datatrace/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	ctxUserIDKey   = "auth_user_id"
+	ctxUsernameKey = "auth_username"
+	ctxRoleKey     = "auth_role"
+)
+
+// JWTAuth 校验请求携带的access token，并将用户信息写入上下文；
+// 支持Authorization头（Bearer）或access_token查询参数，后者供SSE的EventSource使用（浏览器无法为其设置请求头）
+func JWTAuth() gin.HandlerFunc {
+	auth := services.NewAuthService()
+	return func(c *gin.Context) {
+		token := extractToken(c)
+		if token == "" {
+			Unauthorized(c, "缺少认证token")
+			c.Abort()
+			return
+		}
+
+		claims, err := auth.ParseToken(token)
+		if err != nil || claims.Type != "access" {
+			Unauthorized(c, "token无效或已过期")
+			c.Abort()
+			return
+		}
+
+		c.Set(ctxUserIDKey, claims.UserID)
+		c.Set(ctxUsernameKey, claims.Username)
+		c.Set(ctxRoleKey, claims.Role)
+		c.Next()
+	}
+}
+
+// RequireRole 要求当前用户角色属于给定集合之一，必须在JWTAuth之后使用
+func RequireRole(roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(roles))
+	for _, r := range roles {
+		allowed[r] = true
+	}
+
+	return func(c *gin.Context) {
+		role, _ := c.Get(ctxRoleKey)
+		if r, ok := role.(string); !ok || !allowed[r] {
+			Forbidden(c, "权限不足")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireInternalSecret 校验X-Internal-Secret头等于config.Cluster.InternalSecret，
+// 用于/internal路由：集群节点间转发控制请求不带用户JWT，改用共享密钥防止外部未授权调用
+func RequireInternalSecret() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := config.GlobalConfig.Cluster.InternalSecret
+		got := c.GetHeader("X-Internal-Secret")
+		if secret == "" || got == "" || subtle.ConstantTimeCompare([]byte(got), []byte(secret)) != 1 {
+			Unauthorized(c, "内部接口鉴权失败")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentUserID 取出当前登录用户ID，必须在JWTAuth之后调用；未认证时返回空字符串
+func CurrentUserID(c *gin.Context) string {
+	v, _ := c.Get(ctxUserIDKey)
+	s, _ := v.(string)
+	return s
+}
+
+// CurrentUsername 取出当前登录用户名，必须在JWTAuth之后调用
+func CurrentUsername(c *gin.Context) string {
+	v, _ := c.Get(ctxUsernameKey)
+	s, _ := v.(string)
+	return s
+}
+
+// CurrentRole 取出当前登录用户角色，必须在JWTAuth之后调用
+func CurrentRole(c *gin.Context) string {
+	v, _ := c.Get(ctxRoleKey)
+	s, _ := v.(string)
+	return s
+}
+
+// RequestID 取出请求的X-Request-Id头，客户端未传时生成一个新的，供审计日志关联同一次请求
+func RequestID(c *gin.Context) string {
+	if id := c.GetHeader("X-Request-Id"); id != "" {
+		return id
+	}
+	return uuid.New().String()
+}
+
+// extractToken 从Authorization头或access_token查询参数中提取token
+func extractToken(c *gin.Context) string {
+	if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimPrefix(header, "Bearer ")
+	}
+	return c.Query("access_token")
+}
@@ -0,0 +1,31 @@
+package common
+
+import (
+	"time"
+
+	"datatrace/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Logger 请求日志中间件，记录每个请求的方法/路径/状态码/耗时/客户端IP
+func Logger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		if raw := c.Request.URL.RawQuery; raw != "" {
+			path = path + "?" + raw
+		}
+
+		c.Next()
+
+		utils.Log.Info("request",
+			zap.Int("status", c.Writer.Status()),
+			zap.String("method", c.Request.Method),
+			zap.String("path", path),
+			zap.String("client_ip", c.ClientIP()),
+			zap.Duration("latency", time.Since(start)),
+		)
+	}
+}
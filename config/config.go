@@ -7,9 +7,16 @@ import (
 
 // Config 全局配置结构
 type Config struct {
-	Database DatabaseConfig `mapstructure:"database"`
-	Server   ServerConfig   `mapstructure:"server"`
-	Security SecurityConfig `mapstructure:"security"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Security  SecurityConfig  `mapstructure:"security"`
+	Log       LogConfig       `mapstructure:"log"`
+	JWT       JWTConfig       `mapstructure:"jwt"`
+	TaskLog   TaskLogConfig   `mapstructure:"task_log"`
+	Query     QueryConfig     `mapstructure:"query"`
+	Crypto    CryptoConfig    `mapstructure:"crypto"`
+	Cluster   ClusterConfig   `mapstructure:"cluster"`
+	Scheduler SchedulerConfig `mapstructure:"scheduler"`
 }
 
 // DatabaseConfig 数据库配置
@@ -34,6 +41,50 @@ type SecurityConfig struct {
 	EncryptionKey string `mapstructure:"encryption_key"`
 }
 
+// LogConfig 日志配置
+type LogConfig struct {
+	Level      string `mapstructure:"level"`        // 日志级别：debug/info/warn/error
+	FilePath   string `mapstructure:"file_path"`    // 日志文件路径
+	MaxSizeMB  int    `mapstructure:"max_size_mb"`  // 单个日志文件最大大小（MB），超过后滚动
+	MaxBackups int    `mapstructure:"max_backups"`  // 最多保留的旧日志文件数
+	MaxAgeDays int    `mapstructure:"max_age_days"` // 旧日志文件最多保留天数
+	Compress   bool   `mapstructure:"compress"`     // 是否压缩旧日志文件
+	Console    bool   `mapstructure:"console"`      // 是否同时输出到控制台
+}
+
+// JWTConfig JWT鉴权配置
+type JWTConfig struct {
+	Secret             string `mapstructure:"secret"`               // HS256签名密钥
+	AccessTokenMinutes int    `mapstructure:"access_token_minutes"` // access token有效期（分钟）
+	RefreshTokenHours  int    `mapstructure:"refresh_token_hours"`  // refresh token有效期（小时）
+}
+
+// TaskLogConfig 任务日志存储配置
+type TaskLogConfig struct {
+	StoreBackend string `mapstructure:"store_backend"` // 存储后端：memory（内存环形缓冲区，重启丢失）/gorm（持久化到task_logs表）
+}
+
+// QueryConfig 即席查询（预览）配置
+type QueryConfig struct {
+	MaxPreviewLimit int `mapstructure:"max_preview_limit"` // 预览接口允许的最大limit，客户端传入的limit超过此值会被截断
+}
+
+// CryptoConfig 信封加密RSA密钥轮换配置
+type CryptoConfig struct {
+	RotationPeriodDays   int `mapstructure:"rotation_period_days"`   // 激活密钥达到此天数后视为到期，需要轮换
+	CheckIntervalMinutes int `mapstructure:"check_interval_minutes"` // 后台轮换检查协程的轮询间隔（分钟）
+}
+
+// ClusterConfig 集群节点间内部接口配置
+type ClusterConfig struct {
+	InternalSecret string `mapstructure:"internal_secret"` // 节点间转发控制请求时携带的共享密钥，/internal路由用它替代JWT鉴权
+}
+
+// SchedulerConfig 跨任务全局调度配置
+type SchedulerConfig struct {
+	MaxGlobalWorkers int `mapstructure:"max_global_workers"` // 所有运行中任务共享的全局worker上限，防止单个大任务占满所有并发
+}
+
 var GlobalConfig *Config
 
 // LoadConfig 加载配置文件
@@ -41,6 +92,32 @@ func LoadConfig(configPath string) error {
 	viper.SetConfigFile(configPath)
 	viper.SetConfigType("yaml")
 
+	// 日志配置默认值，配置文件未指定时生效
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.file_path", "logs/datatrace.log")
+	viper.SetDefault("log.max_size_mb", 100)
+	viper.SetDefault("log.max_backups", 7)
+	viper.SetDefault("log.max_age_days", 30)
+	viper.SetDefault("log.compress", true)
+	viper.SetDefault("log.console", true)
+
+	// JWT配置默认值，配置文件未指定时生效
+	viper.SetDefault("jwt.access_token_minutes", 120)
+	viper.SetDefault("jwt.refresh_token_hours", 168)
+
+	// 任务日志存储配置默认值，配置文件未指定时生效
+	viper.SetDefault("task_log.store_backend", "memory")
+
+	// 即席查询配置默认值，配置文件未指定时生效
+	viper.SetDefault("query.max_preview_limit", 500)
+
+	// RSA密钥轮换配置默认值，配置文件未指定时生效
+	viper.SetDefault("crypto.rotation_period_days", 30)
+	viper.SetDefault("crypto.check_interval_minutes", 1440)
+
+	// 全局调度配置默认值，配置文件未指定时生效
+	viper.SetDefault("scheduler.max_global_workers", 16)
+
 	// 读取配置文件
 	if err := viper.ReadInConfig(); err != nil {
 		return fmt.Errorf("读取配置文件失败: %w", err)
@@ -76,6 +153,12 @@ func validateConfig() error {
 	if len(GlobalConfig.Security.EncryptionKey) != 32 {
 		return fmt.Errorf("加密密钥必须是32字节")
 	}
+	if GlobalConfig.JWT.Secret == "" {
+		return fmt.Errorf("JWT密钥不能为空")
+	}
+	if GlobalConfig.Cluster.InternalSecret == "" {
+		return fmt.Errorf("集群内部接口共享密钥(cluster.internal_secret)不能为空")
+	}
 	return nil
 }
 
@@ -59,6 +59,13 @@ func autoMigrate() error {
 		&models.SyncTask{},
 		&models.TaskUnitConfig{},
 		&models.TaskUnitRuntime{},
+		&models.WorkerNode{},
+		&models.TaskUnitStage{},
+		&models.EncryptionKey{},
+		&models.User{},
+		&models.TaskLog{},
+		&models.TaskLease{},
+		&models.AuditLog{},
 	)
 
 	if err != nil {
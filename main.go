@@ -4,10 +4,13 @@ import (
 	"datatrace/config"
 	"datatrace/database"
 	"datatrace/routers"
+	"datatrace/services"
+	"datatrace/utils"
 	"fmt"
 	"log"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 func main() {
@@ -16,28 +19,47 @@ func main() {
 		log.Fatalf("❌ 加载配置失败: %v", err)
 	}
 
+	// 1.1 初始化结构化日志
+	if err := utils.InitLogger(config.GlobalConfig.Log); err != nil {
+		log.Fatalf("❌ 初始化日志失败: %v", err)
+	}
+	defer utils.Log.Sync()
+
 	// 2. 初始化数据库
 	if err := database.InitDB(); err != nil {
 		log.Fatalf("❌ 初始化数据库失败: %v", err)
 	}
 	defer database.CloseDB()
 
+	// 2.1 首次启动（users表为空）时创建默认管理员账号，打印一次性密码供登录后立即修改
+	if otp, err := services.NewUserService().BootstrapDefaultAdmin(); err != nil {
+		log.Fatalf("❌ 初始化默认管理员失败: %v", err)
+	} else if otp != "" {
+		utils.Log.Warn("首次启动，已创建默认管理员账号，请登录后立即修改密码",
+			zap.String("username", "admin"),
+			zap.String("password", otp),
+		)
+	}
+
 	// 3. 设置 Gin 模式
 	gin.SetMode(config.GlobalConfig.Server.Mode)
 
+	// 3.1 启动集群节点心跳（多实例共享同一数据库时，用于节点亲和与失联回收）
+	services.NewNodeRegistryService().Start()
+
+	// 3.2 启动RSA信封加密密钥的定期轮换检查
+	services.NewKeyRotationService().Start()
+
 	// 4. 设置路由
 	r := routers.SetupRouter()
 
 	// 5. 启动服务器
 	addr := fmt.Sprintf(":%d", config.GlobalConfig.Server.Port)
-	log.Println("========================================")
-	log.Println("🚀 DataTrace 数据同步系统")
-	log.Println("========================================")
-	log.Printf("✅ 服务启动成功")
-	log.Printf("📍 监听地址: http://localhost%s", addr)
-	log.Printf("🏥 健康检查: http://localhost%s/health", addr)
-	log.Printf("📚 API文档: http://localhost%s/api/v1", addr)
-	log.Println("========================================")
+	utils.Log.Info("✅ 服务启动成功",
+		zap.String("listen_addr", fmt.Sprintf("http://localhost%s", addr)),
+		zap.String("health_check", fmt.Sprintf("http://localhost%s/health", addr)),
+		zap.String("api_docs", fmt.Sprintf("http://localhost%s/api/v1", addr)),
+	)
 
 	if err := r.Run(addr); err != nil {
 		log.Fatalf("❌ 启动服务器失败: %v", err)
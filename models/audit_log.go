@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditLog 操作审计日志：记录谁在什么时候对哪个对象做了什么操作，以及操作前后的状态；只追加不修改
+type AuditLog struct {
+	ID         string    `gorm:"primaryKey;size:36" json:"id"`
+	ActorID    string    `gorm:"size:36;index" json:"actor_id"`
+	Actor      string    `gorm:"size:100;index" json:"actor"`
+	Action     string    `gorm:"size:50;not null;index" json:"action"`         // 如 task.start/task.pause/task.stop/datasource.create/datasource.update/datasource.delete
+	TargetType string    `gorm:"size:50;not null;index" json:"target_type"`    // task/datasource
+	TargetID   string    `gorm:"size:36;not null;index" json:"target_id"`
+	Before     string    `gorm:"type:text" json:"before,omitempty"` // 操作前状态的JSON快照，创建类操作留空
+	After      string    `gorm:"type:text" json:"after,omitempty"`  // 操作后状态的JSON快照，删除类操作留空
+	RequestID  string    `gorm:"size:64;index" json:"request_id,omitempty"`
+	SourceIP   string    `gorm:"size:64" json:"source_ip,omitempty"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -8,12 +8,13 @@ import (
 type DataSource struct {
 	ID           string    `gorm:"primaryKey;size:36" json:"id"`
 	Name         string    `gorm:"size:100;not null;index" json:"name"`
-	Type         string    `gorm:"size:20;not null;index" json:"type"` // mysql/elasticsearch
+	Type         string    `gorm:"size:20;not null;index" json:"type"` // mysql/postgresql/sqlserver/mongodb/elasticsearch/sqlite
 	Host         string    `gorm:"size:255;not null" json:"host"`
 	Port         int       `gorm:"not null" json:"port"`
 	Username     string    `gorm:"size:100;not null" json:"username"`
-	Password     string    `gorm:"size:255;not null" json:"password"` // 加密存储
+	Password     string    `gorm:"type:text;not null" json:"password"` // 信封加密存储，格式为 版本号:base64(RSA加密的数据密钥):base64(密文)
 	DatabaseName string    `gorm:"size:100" json:"database_name"`     // MySQL专用
+	UseSSL       bool      `gorm:"not null;default:false" json:"use_ssl"` // Elasticsearch专用：是否使用HTTPS协议
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
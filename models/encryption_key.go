@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// EncryptionKey 信封加密使用的RSA密钥版本记录。每次轮换生成新版本并设为激活，
+// 旧版本保留 Active=false 但不删除，用于解密早前用该版本加密的存量数据。密钥本身对集群内
+// 所有节点共享可见（数据源可能在任意节点上被读取/写入），NodeID仅记录生成该版本的节点，
+// 供审计排查使用，不作为访问隔离边界
+type EncryptionKey struct {
+	ID         string    `gorm:"primaryKey;size:36" json:"id"`
+	Version    int       `gorm:"not null;uniqueIndex" json:"version"`
+	PublicKey  string    `gorm:"type:text;not null" json:"-"`
+	PrivateKey string    `gorm:"type:text;not null" json:"-"` // 使用主密钥(AES-GCM)加密后存储
+	Active     bool      `gorm:"not null;default:false;index" json:"active"`
+	NodeID     string    `gorm:"size:64;index" json:"node_id,omitempty"` // 生成该版本密钥的节点标识
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (EncryptionKey) TableName() string {
+	return "encryption_keys"
+}
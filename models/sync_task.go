@@ -10,12 +10,15 @@ type SyncTask struct {
 	Name       string    `gorm:"size:100;not null" json:"name"`
 	SourceID   string    `gorm:"size:36;index" json:"source_id"`                    // 可空，不使用外键
 	TargetID   string    `gorm:"size:36;index" json:"target_id"`                    // 可空，不使用外键
-	SourceType string    `gorm:"size:20;not null" json:"source_type"`               // mysql/elasticsearch
-	TargetType string    `gorm:"size:20;not null" json:"target_type"`               // mysql/elasticsearch
+	SourceType string    `gorm:"size:20;not null" json:"source_type"`               // mysql/postgresql/sqlserver/mongodb/elasticsearch
+	TargetType string    `gorm:"size:20;not null" json:"target_type"`               // mysql/postgresql/sqlserver/mongodb/elasticsearch
 	Config     string    `gorm:"type:text;not null" json:"config"`                  // JSON格式配置
 	Status     string    `gorm:"size:20;not null;default:idle;index" json:"status"` // idle/configured（配置状态）
 	IsRunning  bool      `gorm:"not null;default:false;index" json:"is_running"`    // 是否正在运行
-	SyncMode   string    `gorm:"size:20;not null;default:full" json:"sync_mode"`    // full/incremental
+	SyncMode   string    `gorm:"size:20;not null;default:full" json:"sync_mode"`    // full/incremental/binlog
+	Priority   int       `gorm:"not null;default:5;index" json:"priority"`          // 调度优先级，数字越小优先级越高
+	EndTime    *time.Time `json:"end_time,omitempty"`                               // 可选截止时间，参与调度排序
+	SpecifyIP  string    `gorm:"size:64;default:''" json:"specify_ip"`              // 节点亲和：指定只能被某个IP的节点认领，空或"any"表示不限制
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// TaskLease 任务级别的所有权租约：同一时刻只有一个节点持有某个taskID的租约，
+// 该节点才可以运行其Worker并处理PauseTask/StopTask；租约带TTL，心跳停止后由其他节点接管
+type TaskLease struct {
+	TaskID    string    `gorm:"primaryKey;size:36" json:"task_id"`
+	NodeID    string    `gorm:"size:64;not null;index" json:"node_id"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (TaskLease) TableName() string {
+	return "task_leases"
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// TaskLog 任务日志持久化模型，由GORM后端的LogStore写入/检索
+type TaskLog struct {
+	ID       uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TaskID   string    `gorm:"size:36;not null;index:idx_task_logs_task_level;index:idx_task_logs_task_category" json:"task_id"`
+	Ts       time.Time `gorm:"not null;index" json:"ts"`
+	Level    string    `gorm:"size:20;not null;index:idx_task_logs_task_level" json:"level"`       // info/success/warning/error
+	Category string    `gorm:"size:20;not null;index:idx_task_logs_task_category" json:"category"` // all/create/sync/complete
+	Message  string    `gorm:"type:text;not null" json:"message"`
+	Fields   string    `gorm:"type:text" json:"fields"` // 结构化上下文（unit/batch/rows/duration_ms等），JSON编码后存储
+}
+
+// TableName 指定表名
+func (TaskLog) TableName() string {
+	return "task_logs"
+}
@@ -11,6 +11,16 @@ type TaskUnitConfig struct {
 	UnitName  string    `gorm:"size:200;not null;uniqueIndex:uk_task_unit" json:"unit_name"`    // 表名或索引名
 	UnitType  string    `gorm:"size:20;not null;index" json:"unit_type"`                        // table/index
 	CreatedAt time.Time `json:"created_at"`
+
+	// 断点续传配置：留空时全量同步自动探测单列主键，增量同步沿用sync_config中的表级配置
+	IncrementalColumn string `gorm:"size:100" json:"incremental_column,omitempty"` // 续传使用的水位线列
+	IncrementalMode   string `gorm:"size:20" json:"incremental_mode,omitempty"`    // pk/timestamp/none，none表示禁用断点续传
+
+	// 单元级调度优先级：0表示未单独设置，调度时沿用所属任务的Priority；用于把同一任务内的
+	// 某张热点表提到队列前面，而不必调整整个任务的优先级
+	Priority int `gorm:"default:0" json:"priority,omitempty"`
+	// Weight 预留给未来的加权公平调度（同优先级单元之间按权重分配worker），当前仅持久化，调度暂不消费
+	Weight int `gorm:"default:0" json:"weight,omitempty"`
 }
 
 // TableName 指定表名
@@ -15,7 +15,21 @@ type TaskUnitRuntime struct {
 	ErrorMessage       string     `gorm:"type:text" json:"error_message,omitempty"`
 	StartedAt          *time.Time `json:"started_at,omitempty"`
 	UpdatedAt          time.Time  `json:"updated_at"`
-	LastProcessedBatch *int       `json:"last_processed_batch,omitempty"` // 预留字段
+	LastProcessedBatch *int       `json:"last_processed_batch,omitempty"`
+	LastWatermark      string     `gorm:"type:text" json:"last_watermark,omitempty"` // 断点续传水位线：上次成功写入批次中续传列的最大值
+
+	// 增量同步水位线，仅在 sync_mode=incremental 时使用
+	WatermarkColumn string `gorm:"size:100" json:"watermark_column,omitempty"` // 水位线列名（主键或更新时间列）
+	WatermarkValue  string `gorm:"type:text" json:"watermark_value,omitempty"` // 上次同步到的水位线值（字符串化存储）
+	WatermarkType   string `gorm:"size:20" json:"watermark_type,omitempty"`    // 水位线列的数据类型：int/time/string
+
+	// 节点亲和
+	SpecifyIP string `gorm:"size:64;default:''" json:"specify_ip,omitempty"` // 从所属任务继承，留空或"any"表示任意节点可认领
+	NodeID    string `gorm:"size:64;index" json:"node_id,omitempty"`         // 当前认领该单元的节点ID
+
+	// Priority 单元级调度优先级，从TaskUnitConfig.Priority复制而来；启动任务入队时如果为0则沿用
+	// 所属任务的Priority。可通过TaskControlService.ReprioritizeUnit单独调整，不影响同任务的其它单元
+	Priority int `gorm:"default:0" json:"priority,omitempty"`
 }
 
 // TableName 指定表名
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// TaskUnitStage 任务单元的流水线阶段记录，对应 prepare_schema -> truncate_or_create -> copy_data ->
+// build_indexes -> verify_rowcount -> verify_checksum 六个固定阶段，每个阶段独立记录状态，可单独重跑
+type TaskUnitStage struct {
+	ID          string     `gorm:"primaryKey;size:36" json:"id"`
+	TaskID      string     `gorm:"size:36;not null;index" json:"task_id"`
+	UnitID      string     `gorm:"size:36;not null;uniqueIndex:uk_unit_stage" json:"unit_id"`
+	StageName   string     `gorm:"size:40;not null;uniqueIndex:uk_unit_stage" json:"stage_name"`
+	Status      string     `gorm:"size:20;not null;default:pending" json:"status"` // pending/running/completed/failed/skipped
+	PlannedAt   time.Time  `json:"planned_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Error       string     `gorm:"type:text" json:"error,omitempty"`
+}
+
+// TableName 指定表名
+func (TaskUnitStage) TableName() string {
+	return "task_unit_stages"
+}
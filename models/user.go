@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// User 用户模型
+type User struct {
+	ID           string    `gorm:"primaryKey;size:36" json:"id"`
+	Username     string    `gorm:"size:50;not null;uniqueIndex" json:"username"`
+	PasswordHash string    `gorm:"size:100;not null" json:"-"`
+	Role         string    `gorm:"size:20;not null;index" json:"role"` // admin/operator/viewer
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (User) TableName() string {
+	return "users"
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// WorkerNode 集群节点心跳记录，多个datatrace实例共享同一个数据库时用于互相发现和存活判断
+type WorkerNode struct {
+	NodeID        string    `gorm:"primaryKey;size:64" json:"node_id"`
+	Hostname      string    `gorm:"size:255;not null" json:"hostname"`
+	IP            string    `gorm:"size:64;not null;index" json:"ip"`
+	Port          int       `gorm:"not null;default:0" json:"port"`     // 节点HTTP服务端口，用于转发任务控制请求
+	Capacity      int       `gorm:"not null;default:1" json:"capacity"` // 可并发处理的任务单元数
+	LastHeartbeat time.Time `gorm:"not null;index" json:"last_heartbeat"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (WorkerNode) TableName() string {
+	return "worker_nodes"
+}
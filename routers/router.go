@@ -13,8 +13,7 @@ func SetupRouter() *gin.Engine {
 
 	// 使用中间件
 	r.Use(gin.Recovery())
-	// 只在 debug 模式下使用日志中间件
-	// r.Use(common.Logger())
+	r.Use(common.Logger())
 
 	// 加载 HTML 模板
 	r.LoadHTMLGlob("templates/**/*.html")
@@ -42,8 +41,32 @@ func SetupRouter() *gin.Engine {
 		})
 	})
 
+	// 集群节点间内部接口，不做JWT鉴权（调用方是其他节点而非登录用户）：ClusterService.ForwardControl
+	// 在任务的租约owner不是当前接收请求的节点时，把Pause/Stop转发到这里。改用共享密钥鉴权，
+	// 防止能访问该端口的任意外部客户端绕过RBAC暂停/停止任务
+	taskControlAPI := api.NewTaskControlAPI()
+	internalGroup := r.Group("/internal/tasks/:id")
+	internalGroup.Use(common.RequireInternalSecret())
+	{
+		internalGroup.POST("/pause", taskControlAPI.InternalPause)
+		internalGroup.POST("/stop", taskControlAPI.InternalStop)
+	}
+
 	// API 路由组
 	apiGroup := r.Group("/api/v1")
+
+	// 认证相关接口，无需鉴权
+	authAPI := api.NewAuthAPI()
+	authGroup := apiGroup.Group("/auth")
+	{
+		authGroup.POST("/login", authAPI.Login)
+		authGroup.POST("/refresh", authAPI.Refresh)
+		authGroup.POST("/logout", authAPI.Logout)
+	}
+
+	// 其余 /api/v1 接口统一要求JWT鉴权
+	// SSE接口通过 ?access_token= 查询参数传递token（EventSource无法设置请求头）
+	apiGroup.Use(common.JWTAuth())
 	{
 		// 数据源管理
 		dsAPI := api.NewDataSourceAPI()
@@ -51,43 +74,72 @@ func SetupRouter() *gin.Engine {
 		datasources := apiGroup.Group("/datasources")
 		{
 			datasources.GET("", dsAPI.List)
-			datasources.POST("", dsAPI.Create)
+			datasources.POST("", common.RequireRole("admin", "operator"), dsAPI.Create)
 			datasources.POST("/test", dsAPI.TestConnection)
 			datasources.GET("/:id", dsAPI.GetByID)
 			datasources.POST("/:id/test", dsAPI.TestConnectionByID)
+			datasources.POST("/:id/preview", common.RequireRole("admin", "operator"), dsAPI.Preview)
 			datasources.GET("/:id/databases", dsAPI.GetDatabases)
 			datasources.GET("/:id/tables", dsAPI.GetTables)
-			datasources.PUT("/:id", dsAPI.Update)
-			datasources.DELETE("/:id", dsAPI.Delete)
+			datasources.GET("/:id/pool-stats", dsAPI.PoolStats)
+			datasources.PUT("/:id", common.RequireRole("admin", "operator"), dsAPI.Update)
+			datasources.DELETE("/:id", common.RequireRole("admin"), dsAPI.Delete)
 
-			// SSE流式推送测试结果
+			// SSE流式推送测试结果/预览结果
 			datasources.GET("/test/stream", dsSSEAPI.StreamTestResults)
+			datasources.GET("/:id/preview/stream", common.RequireRole("admin", "operator"), dsSSEAPI.StreamQueryResults)
 		}
 
 		// 任务管理
 		taskAPI := api.NewTaskAPI()
 		taskMonitorAPI := api.NewTaskMonitorAPI()
-		taskControlAPI := api.NewTaskControlAPI()
 		taskSSEAPI := api.NewTaskSSEAPI()
 		tasks := apiGroup.Group("/tasks")
 		{
 			tasks.GET("", taskAPI.List)
-			tasks.POST("", taskAPI.Create)
+			tasks.POST("", common.RequireRole("admin", "operator"), taskAPI.Create)
 			tasks.GET("/:id", taskAPI.GetByID)
-			tasks.PUT("/:id/config", taskAPI.UpdateConfig)
-			tasks.DELETE("/:id", taskAPI.Delete)
+			tasks.PUT("/:id/config", common.RequireRole("admin", "operator"), taskAPI.UpdateConfig)
+			tasks.DELETE("/:id", common.RequireRole("admin", "operator"), taskAPI.Delete)
 
 			// 任务监控
 			tasks.GET("/:id/progress", taskMonitorAPI.GetProgress)
 			tasks.GET("/:id/logs", taskMonitorAPI.GetLogs)
 
 			// 任务控制
-			tasks.POST("/:id/start", taskControlAPI.Start)
-			tasks.POST("/:id/pause", taskControlAPI.Pause)
-			tasks.POST("/:id/stop", taskControlAPI.Stop)
+			tasks.POST("/:id/start", common.RequireRole("admin", "operator"), taskControlAPI.Start)
+			tasks.POST("/:id/pause", common.RequireRole("admin", "operator"), taskControlAPI.Pause)
+			tasks.POST("/:id/stop", common.RequireRole("admin", "operator"), taskControlAPI.Stop)
+			tasks.PUT("/:id/priority", common.RequireRole("admin", "operator"), taskControlAPI.Reprioritize)
+			tasks.POST("/:id/units/:unit/reset-checkpoint", common.RequireRole("admin", "operator"), taskControlAPI.ResetCheckpoint)
+			tasks.POST("/:id/units/:unit/reprioritize", common.RequireRole("admin", "operator"), taskControlAPI.ReprioritizeUnit)
 
 			// SSE流式推送
 			tasks.GET("/:id/stream", taskSSEAPI.StreamTaskUpdates)
+			tasks.GET("/:id/progress/stream", taskSSEAPI.StreamTaskProgress)
+			tasks.GET("/:id/logs/stream", taskSSEAPI.StreamTaskLogs)
+		}
+
+		// 多任务SSE多路复用，?task_ids=a,b,c 在一条连接上同时watch多个任务
+		apiGroup.GET("/events", taskSSEAPI.StreamEvents)
+
+		// 用户管理，仅管理员可操作
+		userAPI := api.NewUserAPI()
+		users := apiGroup.Group("/users")
+		users.Use(common.RequireRole("admin"))
+		{
+			users.GET("", userAPI.List)
+			users.POST("", userAPI.Create)
+			users.DELETE("/:id", userAPI.Delete)
+		}
+
+		// 信封加密密钥管理，仅管理员可操作
+		cryptoAPI := api.NewCryptoAPI()
+		cryptoGroup := apiGroup.Group("/crypto")
+		cryptoGroup.Use(common.RequireRole("admin"))
+		{
+			cryptoGroup.POST("/rotate", cryptoAPI.Rotate)
+			cryptoGroup.GET("/keys", cryptoAPI.ListKeys)
 		}
 	}
 
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"encoding/json"
+
+	"datatrace/database"
+	"datatrace/models"
+
+	"github.com/google/uuid"
+)
+
+// AuditActor 操作人信息，由调用方从JWT鉴权上下文中解析得到
+type AuditActor struct {
+	UserID   string
+	Username string
+}
+
+// AuditService 记录任务控制、数据源变更等关键操作的审计日志
+type AuditService struct{}
+
+// NewAuditService 创建审计服务
+func NewAuditService() *AuditService {
+	return &AuditService{}
+}
+
+// Record 写入一条审计日志；before/after序列化为JSON快照，传nil表示该操作没有对应的前/后态
+// （如StartTask/PauseTask/StopTask这类没有字段变更的控制类操作）
+func (s *AuditService) Record(actor AuditActor, action, targetType, targetID string, before, after interface{}, requestID, sourceIP string) error {
+	entry := &models.AuditLog{
+		ID:         uuid.New().String(),
+		ActorID:    actor.UserID,
+		Actor:      actor.Username,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     marshalAuditSnapshot(before),
+		After:      marshalAuditSnapshot(after),
+		RequestID:  requestID,
+		SourceIP:   sourceIP,
+	}
+	return database.DB.Create(entry).Error
+}
+
+// marshalAuditSnapshot 把操作前后的状态序列化为JSON字符串，序列化失败时记录为空而不是中断审计
+func marshalAuditSnapshot(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"datatrace/config"
+	"datatrace/database"
+	"datatrace/models"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// tokenType 区分access token与refresh token，避免refresh token被当作access token使用
+type tokenType string
+
+const (
+	tokenTypeAccess  tokenType = "access"
+	tokenTypeRefresh tokenType = "refresh"
+
+	blacklistCleanupInterval = 10 * time.Minute
+)
+
+// Claims JWT自定义声明
+type Claims struct {
+	UserID   string    `json:"user_id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	Type     tokenType `json:"type"`
+	jwt.RegisteredClaims
+}
+
+// AuthService 负责密码哈希校验、JWT签发/解析，以及已登出token的黑名单
+type AuthService struct {
+	blacklist sync.Map // token字符串 -> 过期时间，后台定期清理
+}
+
+var (
+	authInstance *AuthService
+	authOnce     sync.Once
+)
+
+// NewAuthService 获取鉴权服务单例
+func NewAuthService() *AuthService {
+	authOnce.Do(func() {
+		authInstance = &AuthService{}
+		go authInstance.cleanupBlacklistLoop()
+	})
+	return authInstance
+}
+
+// LoginRequest 登录请求
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// LoginResponse 登录/刷新响应
+type LoginResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"` // access token有效期，单位秒
+}
+
+// Login 校验用户名密码，成功后签发access/refresh token
+func (s *AuthService) Login(req *LoginRequest) (*LoginResponse, error) {
+	var user models.User
+	if err := database.DB.First(&user, "username = ?", req.Username).Error; err != nil {
+		return nil, errors.New("用户名或密码错误")
+	}
+	if !s.VerifyPassword(user.PasswordHash, req.Password) {
+		return nil, errors.New("用户名或密码错误")
+	}
+
+	accessToken, err := s.GenerateAccessToken(&user)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := s.GenerateRefreshToken(&user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    config.GlobalConfig.JWT.AccessTokenMinutes * 60,
+	}, nil
+}
+
+// Refresh 使用有效的refresh token换取新的access token
+func (s *AuthService) Refresh(refreshToken string) (*LoginResponse, error) {
+	claims, err := s.ParseToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type != tokenTypeRefresh {
+		return nil, errors.New("无效的refresh token")
+	}
+
+	user := &models.User{ID: claims.UserID, Username: claims.Username, Role: claims.Role}
+	accessToken, err := s.GenerateAccessToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   config.GlobalConfig.JWT.AccessTokenMinutes * 60,
+	}, nil
+}
+
+// Logout 将access token加入黑名单，使其在过期前立即失效
+func (s *AuthService) Logout(tokenString string) error {
+	claims, err := s.ParseToken(tokenString)
+	if err != nil {
+		return nil // token本就无效，视为登出成功
+	}
+	s.blacklist.Store(tokenString, claims.ExpiresAt.Time)
+	return nil
+}
+
+// HashPassword 使用bcrypt生成密码哈希
+func (s *AuthService) HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// VerifyPassword 校验明文密码是否匹配哈希
+func (s *AuthService) VerifyPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// GenerateAccessToken 签发access token
+func (s *AuthService) GenerateAccessToken(user *models.User) (string, error) {
+	ttl := time.Duration(config.GlobalConfig.JWT.AccessTokenMinutes) * time.Minute
+	return s.generateToken(user, tokenTypeAccess, ttl)
+}
+
+// GenerateRefreshToken 签发refresh token
+func (s *AuthService) GenerateRefreshToken(user *models.User) (string, error) {
+	ttl := time.Duration(config.GlobalConfig.JWT.RefreshTokenHours) * time.Hour
+	return s.generateToken(user, tokenTypeRefresh, ttl)
+}
+
+// generateToken 签发指定类型和有效期的HS256 token
+func (s *AuthService) generateToken(user *models.User, typ tokenType, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+		Type:     typ,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(config.GlobalConfig.JWT.Secret))
+}
+
+// ParseToken 校验签名和有效期并解析token，已登出（黑名单中）的token视为无效
+func (s *AuthService) ParseToken(tokenString string) (*Claims, error) {
+	if _, blacklisted := s.blacklist.Load(tokenString); blacklisted {
+		return nil, errors.New("token已失效")
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(config.GlobalConfig.JWT.Secret), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, errors.New("无效的token")
+	}
+	return claims, nil
+}
+
+// cleanupBlacklistLoop 周期清理已过期的黑名单条目，避免内存无限增长
+func (s *AuthService) cleanupBlacklistLoop() {
+	ticker := time.NewTicker(blacklistCleanupInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		now := time.Now()
+		s.blacklist.Range(func(key, value interface{}) bool {
+			if exp, ok := value.(time.Time); ok && now.After(exp) {
+				s.blacklist.Delete(key)
+			}
+			return true
+		})
+	}
+}
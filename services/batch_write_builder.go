@@ -0,0 +1,243 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// WriteMode 批量写入的冲突处理策略，来自任务的同步配置
+type WriteMode string
+
+const (
+	WriteModeInsert       WriteMode = "insert"       // 普通INSERT，遇唯一键冲突整行失败
+	WriteModeInsertIgnore WriteMode = "insert_ignore" // 冲突时忽略该行，不报错
+	WriteModeUpsert       WriteMode = "upsert"        // 冲突时更新该行（增量同步默认使用）
+	WriteModeReplace      WriteMode = "replace"       // 先删后插
+)
+
+// defaultBatchWriteChunkSize BatchInTx未显式设置时的默认事务分片大小
+const defaultBatchWriteChunkSize = 500
+
+// RowError 批量写入中某一行的失败记录，Index为该行在入参records切片中的位置
+type RowError struct {
+	Index int
+	Err   error
+}
+
+// BatchWriteBuilder 链式构造批量写入SQL的小工具，按dialect翻译为对应方言的冲突处理语法，
+// 屏蔽MySQL/PostgreSQL/SQLite在INSERT IGNORE、UPSERT、REPLACE上的语法差异
+type BatchWriteBuilder struct {
+	dialect      string // mysql/postgresql/sqlite
+	table        string
+	mode         WriteMode
+	conflictCols []string
+	updateCols   []string
+	chunkSize    int
+}
+
+// NewBatchWriteBuilder 创建批量写入构造器，默认模式为普通INSERT，分片大小为defaultBatchWriteChunkSize
+func NewBatchWriteBuilder(dialect, table string) *BatchWriteBuilder {
+	return &BatchWriteBuilder{
+		dialect:   dialect,
+		table:     table,
+		mode:      WriteModeInsert,
+		chunkSize: defaultBatchWriteChunkSize,
+	}
+}
+
+// Insert 普通插入
+func (b *BatchWriteBuilder) Insert() *BatchWriteBuilder {
+	b.mode = WriteModeInsert
+	return b
+}
+
+// InsertIgnore 冲突时忽略该行
+func (b *BatchWriteBuilder) InsertIgnore() *BatchWriteBuilder {
+	b.mode = WriteModeInsertIgnore
+	return b
+}
+
+// Upsert 冲突时更新指定列；conflictCols为冲突判定列（MySQL的ON DUPLICATE KEY UPDATE无需显式指定，
+// 参数仅供PostgreSQL/SQLite的ON CONFLICT子句使用），updateCols为空时默认更新全部列
+func (b *BatchWriteBuilder) Upsert(conflictCols, updateCols []string) *BatchWriteBuilder {
+	b.mode = WriteModeUpsert
+	b.conflictCols = conflictCols
+	b.updateCols = updateCols
+	return b
+}
+
+// Replace 先删后插（MySQL REPLACE INTO / SQLite INSERT OR REPLACE）；PostgreSQL无REPLACE语法，
+// 退化为对全部列做UPSERT
+func (b *BatchWriteBuilder) Replace() *BatchWriteBuilder {
+	b.mode = WriteModeReplace
+	return b
+}
+
+// BatchInTx 设置每个事务提交的行数，每个分片独立开启事务并提交，避免单行冲突拖垮整批
+func (b *BatchWriteBuilder) BatchInTx(size int) *BatchWriteBuilder {
+	if size > 0 {
+		b.chunkSize = size
+	}
+	return b
+}
+
+// quoteIdent 按方言给标识符加引号
+func (b *BatchWriteBuilder) quoteIdent(name string) string {
+	if b.dialect == "postgresql" || b.dialect == "sqlite" {
+		return fmt.Sprintf(`"%s"`, name)
+	}
+	return fmt.Sprintf("`%s`", name)
+}
+
+// placeholder 按方言生成第n个(从1开始)占位符
+func (b *BatchWriteBuilder) placeholder(n int) string {
+	if b.dialect == "postgresql" {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// buildQuery 为一组行构造完整的写入语句，返回SQL与按行展开的参数列表
+func (b *BatchWriteBuilder) buildQuery(columns []string, rows []map[string]interface{}) (string, []interface{}) {
+	columnNames := make([]string, len(columns))
+	for i, col := range columns {
+		columnNames[i] = b.quoteIdent(col)
+	}
+
+	placeholders := make([]string, len(rows))
+	values := make([]interface{}, 0, len(rows)*len(columns))
+	n := 1
+	for i, row := range rows {
+		rowPlaceholders := make([]string, len(columns))
+		for j, col := range columns {
+			rowPlaceholders[j] = b.placeholder(n)
+			n++
+			values = append(values, row[col])
+		}
+		placeholders[i] = fmt.Sprintf("(%s)", strings.Join(rowPlaceholders, ", "))
+	}
+
+	verb := "INSERT"
+	if b.mode == WriteModeReplace && b.dialect != "postgresql" {
+		verb = "REPLACE"
+	}
+	if b.mode == WriteModeInsertIgnore && b.dialect == "sqlite" {
+		verb = "INSERT OR IGNORE"
+	}
+	if b.mode == WriteModeReplace && b.dialect == "sqlite" {
+		verb = "INSERT OR REPLACE"
+	}
+
+	query := fmt.Sprintf("%s INTO %s (%s) VALUES %s",
+		verb, b.quoteIdent(b.table), strings.Join(columnNames, ", "), strings.Join(placeholders, ", "))
+
+	switch {
+	case b.mode == WriteModeInsertIgnore && b.dialect == "mysql":
+		query = fmt.Sprintf("INSERT IGNORE INTO %s (%s) VALUES %s",
+			b.quoteIdent(b.table), strings.Join(columnNames, ", "), strings.Join(placeholders, ", "))
+	case b.mode == WriteModeInsertIgnore && b.dialect == "postgresql":
+		query += fmt.Sprintf(" ON CONFLICT (%s) DO NOTHING", strings.Join(b.quoteCols(b.conflictCols), ", "))
+	case b.mode == WriteModeUpsert && b.dialect == "mysql":
+		query += fmt.Sprintf(" ON DUPLICATE KEY UPDATE %s", strings.Join(b.mysqlUpdateClauses(columns), ", "))
+	case b.mode == WriteModeUpsert || (b.mode == WriteModeReplace && b.dialect == "postgresql"):
+		query += fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s",
+			strings.Join(b.quoteCols(b.conflictCols), ", "), strings.Join(b.excludedUpdateClauses(columns), ", "))
+	}
+
+	return query, values
+}
+
+// quoteCols 批量加引号
+func (b *BatchWriteBuilder) quoteCols(cols []string) []string {
+	out := make([]string, len(cols))
+	for i, c := range cols {
+		out[i] = b.quoteIdent(c)
+	}
+	return out
+}
+
+// mysqlUpdateClauses 构造 `col` = VALUES(`col`) 子句列表，updateCols为空时覆盖全部列
+func (b *BatchWriteBuilder) mysqlUpdateClauses(columns []string) []string {
+	cols := b.updateCols
+	if len(cols) == 0 {
+		cols = columns
+	}
+	clauses := make([]string, len(cols))
+	for i, col := range cols {
+		clauses[i] = fmt.Sprintf("%s = VALUES(%s)", b.quoteIdent(col), b.quoteIdent(col))
+	}
+	return clauses
+}
+
+// excludedUpdateClauses 构造 "col" = EXCLUDED."col" 子句列表，用于PostgreSQL/SQLite的ON CONFLICT DO UPDATE
+func (b *BatchWriteBuilder) excludedUpdateClauses(columns []string) []string {
+	cols := b.updateCols
+	if len(cols) == 0 {
+		cols = columns
+	}
+	clauses := make([]string, len(cols))
+	for i, col := range cols {
+		clauses[i] = fmt.Sprintf("%s = EXCLUDED.%s", b.quoteIdent(col), b.quoteIdent(col))
+	}
+	return clauses
+}
+
+// Exec 将records按BatchInTx设置的大小分片，每片在独立事务中整批执行；某一片整体失败时退化为
+// 逐行执行以定位出问题的行，使同片内其余行仍能写入。返回的RowError.Index为records中的原始下标，
+// 调用方可据此推进ProcessedRecords并跳过坏行，而不是中止整个批次
+func (b *BatchWriteBuilder) Exec(db *sql.DB, records []map[string]interface{}) ([]RowError, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	var columns []string
+	for col := range records[0] {
+		columns = append(columns, col)
+	}
+
+	chunkSize := b.chunkSize
+	if chunkSize <= 0 {
+		chunkSize = len(records)
+	}
+
+	var failures []RowError
+	for start := 0; start < len(records); start += chunkSize {
+		end := start + chunkSize
+		if end > len(records) {
+			end = len(records)
+		}
+		chunk := records[start:end]
+
+		tx, err := db.Begin()
+		if err != nil {
+			return failures, fmt.Errorf("开启事务失败: %w", err)
+		}
+
+		query, values := b.buildQuery(columns, chunk)
+		if _, err := tx.Exec(query, values...); err == nil {
+			if err := tx.Commit(); err != nil {
+				return failures, fmt.Errorf("提交事务失败: %w", err)
+			}
+			continue
+		}
+		tx.Rollback()
+
+		// 整片失败，逐行重试以隔离出问题的行，其余行在同一事务内继续写入
+		rowTx, err := db.Begin()
+		if err != nil {
+			return failures, fmt.Errorf("开启事务失败: %w", err)
+		}
+		for i, record := range chunk {
+			rowQuery, rowValues := b.buildQuery(columns, []map[string]interface{}{record})
+			if _, err := rowTx.Exec(rowQuery, rowValues...); err != nil {
+				failures = append(failures, RowError{Index: start + i, Err: err})
+			}
+		}
+		if err := rowTx.Commit(); err != nil {
+			return failures, fmt.Errorf("提交事务失败: %w", err)
+		}
+	}
+
+	return failures, nil
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"datatrace/database"
+	"datatrace/models"
+
+	"github.com/go-mysql-org/go-mysql/canal"
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/go-mysql-org/go-mysql/schema"
+)
+
+// binlogEventHandler 将 canal 的行事件翻译为批量写入，复用 MySQLWriter 的 upsert 语义
+type binlogEventHandler struct {
+	canal.DummyEventHandler
+	engine     *SyncEngine
+	taskID     string
+	unit       *models.TaskUnitRuntime
+	writer     *MySQLWriter
+	sourceDB   string
+	sourceTbl  string
+	rowCount   int64
+	lastLogged time.Time
+}
+
+// OnRow 处理一次行变更事件（INSERT/UPDATE/DELETE）
+func (h *binlogEventHandler) OnRow(e *canal.RowsEvent) error {
+	if e.Table.Schema != h.sourceDB || e.Table.Name != h.sourceTbl {
+		return nil
+	}
+
+	switch e.Action {
+	case canal.InsertAction, canal.UpdateAction:
+		// UPDATE 事件的 Rows 中，偶数行是旧值、奇数行是新值，只取新值写入
+		start := 0
+		if e.Action == canal.UpdateAction {
+			start = 1
+		}
+		records := make([]map[string]interface{}, 0, len(e.Rows))
+		for i := start; i < len(e.Rows); i += (start + 1) {
+			records = append(records, rowToRecord(e.Table, e.Rows[i]))
+		}
+		if err := h.writer.WriteBatchUpsert(records); err != nil {
+			return fmt.Errorf("binlog写入失败: %w", err)
+		}
+		h.rowCount += int64(len(records))
+	case canal.DeleteAction:
+		for _, row := range e.Rows {
+			record := rowToRecord(e.Table, row)
+			if err := h.writer.DeleteByPrimaryKey(e.Table, record); err != nil {
+				return fmt.Errorf("binlog删除失败: %w", err)
+			}
+		}
+		h.rowCount += int64(len(e.Rows))
+	}
+
+	h.engine.throttledBinlogProgress(h)
+	return nil
+}
+
+// OnPosSynced canal每次确认binlog位点前移时回调，把位点记入unit的水位线字段（仅内存），
+// 落库频率交给throttledBinlogProgress控制，避免每个事件都写一次task_unit_runtimes
+func (h *binlogEventHandler) OnPosSynced(header *replication.EventHeader, pos mysql.Position, set mysql.GTIDSet, force bool) error {
+	h.unit.WatermarkColumn = "binlog_position"
+	h.unit.WatermarkValue = fmt.Sprintf("%s %d", pos.Name, pos.Pos)
+	return nil
+}
+
+// String 满足 canal.EventHandler 接口的调试标识
+func (h *binlogEventHandler) String() string {
+	return fmt.Sprintf("binlogEventHandler(%s)", h.taskID)
+}
+
+// rowToRecord 将 canal 解析出的单行列值组装为列名->值的 map
+func rowToRecord(table *schema.Table, row []interface{}) map[string]interface{} {
+	record := make(map[string]interface{}, len(table.Columns))
+	for i, col := range table.Columns {
+		if i < len(row) {
+			record[col.Name] = row[i]
+		}
+	}
+	return record
+}
+
+// throttledBinlogProgress 按固定间隔而非每行推送一次进度，避免高频 binlog 事件打爆 SSE
+func (e *SyncEngine) throttledBinlogProgress(h *binlogEventHandler) {
+	if time.Since(h.lastLogged) < time.Second {
+		return
+	}
+	h.lastLogged = time.Now()
+
+	h.unit.ProcessedRecords = h.rowCount
+	updates := map[string]interface{}{
+		"processed_records": h.rowCount,
+		"updated_at":        time.Now(),
+	}
+	if h.unit.WatermarkColumn != "" {
+		updates["watermark_column"] = h.unit.WatermarkColumn
+		updates["watermark_value"] = h.unit.WatermarkValue
+	}
+	database.DB.Model(h.unit).Updates(updates)
+
+	message := fmt.Sprintf("表 %s binlog 订阅中，已处理 %d 条变更", h.unit.UnitName, h.rowCount)
+	e.logService.AddLog(h.taskID, "info", message, "sync")
+	e.sseService.BroadcastLogUpdate(h.taskID, []TaskLog{{
+		Time:     formatLogTime(time.Now()),
+		Level:    "info",
+		Message:  message,
+		Category: "sync",
+	}})
+	e.sseService.BroadcastProgressUpdate(h.taskID)
+}
+
+// runBinlogSync 以 binlog 订阅方式持续同步单个表，直到任务被暂停/停止
+func (e *SyncEngine) runBinlogSync(ctx context.Context, taskID string, unit *models.TaskUnitRuntime, task *models.SyncTask, sourceDB, sourceTable, targetDB, targetTable string, writer *MySQLWriter) error {
+	sourcePassword, err := e.dsService.crypto.Decrypt(task.SourceConn.Password)
+	if err != nil {
+		return e.failUnit(unit, fmt.Sprintf("解密源数据库密码失败: %v", err))
+	}
+
+	cfg := canal.NewDefaultConfig()
+	cfg.Addr = fmt.Sprintf("%s:%d", task.SourceConn.Host, task.SourceConn.Port)
+	cfg.User = task.SourceConn.Username
+	cfg.Password = sourcePassword
+	cfg.Flavor = "mysql"
+	cfg.ServerID = binlogServerID(unit.ID)
+	cfg.Dump.ExecutionPath = "" // 不做 mysqldump 全量导出，仅订阅增量事件
+	cfg.IncludeTableRegex = []string{fmt.Sprintf("^%s\\.%s$", sourceDB, sourceTable)}
+
+	c, err := canal.NewCanal(cfg)
+	if err != nil {
+		return e.failUnit(unit, fmt.Sprintf("创建binlog订阅失败: %v", err))
+	}
+	defer c.Close()
+
+	handler := &binlogEventHandler{
+		engine:    e,
+		taskID:    taskID,
+		unit:      unit,
+		writer:    writer,
+		sourceDB:  sourceDB,
+		sourceTbl: sourceTable,
+	}
+	c.SetEventHandler(handler)
+
+	e.logService.Info(taskID, fmt.Sprintf("表 %s 开始binlog订阅", unit.UnitName))
+
+	// 从上次记录的 binlog 位点恢复，首次启动则从当前位点开始
+	startPos, err := resolveBinlogStartPosition(c, unit)
+	if err != nil {
+		return e.failUnit(unit, fmt.Sprintf("获取binlog起始位点失败: %v", err))
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.RunFrom(startPos)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.Close()
+			return e.pauseUnit(unit, "任务被暂停")
+		case err := <-errCh:
+			if err != nil {
+				return e.failUnit(unit, fmt.Sprintf("binlog订阅中断: %v", err))
+			}
+			return nil
+		}
+	}
+}
+
+// resolveBinlogStartPosition 复用持久化的水位线作为 binlog 起始位点（格式 "file pos"，空格分隔），否则取当前位点
+func resolveBinlogStartPosition(c *canal.Canal, unit *models.TaskUnitRuntime) (mysql.Position, error) {
+	if unit.WatermarkColumn == "binlog_position" && unit.WatermarkValue != "" {
+		var file string
+		var pos uint32
+		if _, err := fmt.Sscanf(unit.WatermarkValue, "%s %d", &file, &pos); err == nil {
+			return mysql.Position{Name: file, Pos: pos}, nil
+		}
+	}
+	return c.GetMasterPos()
+}
+
+// binlogServerID 为每个任务单元生成一个稳定且大概率唯一的 server-id，避免多个订阅互相冲突
+func binlogServerID(unitID string) uint32 {
+	var h uint32 = 2166136261
+	for i := 0; i < len(unitID); i++ {
+		h ^= uint32(unitID[i])
+		h *= 16777619
+	}
+	// 保留在非特权范围内，避免与真实MySQL实例的server-id冲突
+	return 100000 + (h % 900000)
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"datatrace/database"
+	"datatrace/models"
+	"time"
+)
+
+// CheckpointStore 负责推进任务单元的续传断点状态(processed_records/last_processed_batch/
+// last_watermark)，并按 SyncConfig.CheckpointInterval 把状态落库，供PauseTask/StopTask之后的
+// StartTask恢复使用，避免已经同步过的数据重新拷贝一遍
+type CheckpointStore struct{}
+
+// NewCheckpointStore 创建断点存储
+func NewCheckpointStore() *CheckpointStore {
+	return &CheckpointStore{}
+}
+
+// Advance 推进processed_records/批次号/水位线的内存状态，不落库。每个批次都应该调用，
+// 保证同一进程内后续批次的读取游标（unit.LastWatermark/WatermarkValue）始终是最新值，
+// 只有落库频率才受CheckpointInterval控制
+func (c *CheckpointStore) Advance(unit *models.TaskUnitRuntime, batchNum int, processedDelta int64, watermark string) {
+	unit.ProcessedRecords += processedDelta
+	unit.LastProcessedBatch = &batchNum
+	if watermark != "" {
+		unit.LastWatermark = watermark
+	}
+}
+
+// Flush 把当前内存中的续传断点落库。CheckpointInterval>1时不必每个批次都调用，
+// 代价是进程崩溃或被Pause时可能需要重放最近最多CheckpointInterval-1个批次
+func (c *CheckpointStore) Flush(unit *models.TaskUnitRuntime) error {
+	updates := map[string]interface{}{
+		"processed_records":    unit.ProcessedRecords,
+		"last_processed_batch": unit.LastProcessedBatch,
+		"updated_at":           time.Now(),
+	}
+	if unit.LastWatermark != "" {
+		updates["last_watermark"] = unit.LastWatermark
+	}
+	return database.DB.Model(unit).Updates(updates).Error
+}
+
+// Save 推进内存状态并立即落库，等价于Advance+Flush；CheckpointInterval<=1（默认每批次落库）时使用
+func (c *CheckpointStore) Save(unit *models.TaskUnitRuntime, batchNum int, processedDelta int64, watermark string) error {
+	c.Advance(unit, batchNum, processedDelta, watermark)
+	return c.Flush(unit)
+}
+
+// Reset 清除单元的续传断点，供操作员手动重置后从头同步
+func (c *CheckpointStore) Reset(unit *models.TaskUnitRuntime) error {
+	unit.ProcessedRecords = 0
+	unit.LastProcessedBatch = nil
+	unit.LastWatermark = ""
+
+	return database.DB.Model(unit).Updates(map[string]interface{}{
+		"processed_records":    0,
+		"last_processed_batch": nil,
+		"last_watermark":       "",
+		"updated_at":           time.Now(),
+	}).Error
+}
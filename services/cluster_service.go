@@ -0,0 +1,185 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"datatrace/config"
+	"datatrace/database"
+	"datatrace/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	leaseTTL              = 30 * time.Second // 任务租约有效期，与节点心跳超时对齐
+	leaseRenewInterval    = 10 * time.Second // 持有租约的节点续约间隔
+	forwardControlTimeout = 5 * time.Second  // 转发控制请求到owner节点的HTTP超时
+)
+
+// ClusterService 在多个datatrace节点共享同一数据库部署为集群时，负责任务级别的owner选举
+// （task_leases表，带TTL）以及把PauseTask/StopTask转发给真正持有租约的节点。
+// 任务单元级别的节点亲和/失联回收已经由NodeRegistryService+claimUnits处理，
+// ClusterService只解决"谁来执行某个taskID的调度协程、谁来响应它的控制请求"这一层的split-brain问题
+type ClusterService struct {
+	node *NodeRegistryService
+}
+
+var (
+	clusterServiceInstance *ClusterService
+	clusterServiceOnce     sync.Once
+)
+
+// NewClusterService 获取集群服务单例
+func NewClusterService() *ClusterService {
+	clusterServiceOnce.Do(func() {
+		clusterServiceInstance = &ClusterService{node: NewNodeRegistryService()}
+	})
+	return clusterServiceInstance
+}
+
+// AcquireLease 尝试为taskID获取nodeID的租约：租约不存在或已过期时成功接管，
+// 被其他节点持有且未过期时失败，避免StartTask在多个节点上重复调度同一个任务。
+// 接管通过一条带WHERE条件的UPDATE原子完成（而不是先读后写），按RowsAffected判断是否抢到租约，
+// 避免两个节点同时读到"已过期"后都各自执行无条件UPDATE、都以为自己拿到了租约的竞态
+func (c *ClusterService) AcquireLease(taskID, nodeID string) error {
+	now := time.Now()
+	expiresAt := now.Add(leaseTTL)
+
+	result := database.DB.Model(&models.TaskLease{}).
+		Where("task_id = ? AND (node_id = ? OR expires_at <= ?)", taskID, nodeID, now).
+		Updates(map[string]interface{}{
+			"node_id":    nodeID,
+			"expires_at": expiresAt,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil
+	}
+
+	// 没有命中：要么租约还不存在（首次启动该任务），要么被其他节点持有且未过期
+	var lease models.TaskLease
+	err := database.DB.Where("task_id = ?", taskID).First(&lease).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// TaskID是主键，两个节点同时Create时后到者会因主键冲突报错，不会出现双写
+		return database.DB.Create(&models.TaskLease{
+			TaskID:    taskID,
+			NodeID:    nodeID,
+			ExpiresAt: expiresAt,
+			UpdatedAt: now,
+		}).Error
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("任务当前由节点 %s 持有租约（%s后过期），无法在本节点启动", lease.NodeID, time.Until(lease.ExpiresAt).Round(time.Second))
+}
+
+// RenewLease 续约，只有仍然持有该taskID租约的节点才能续约成功
+func (c *ClusterService) RenewLease(taskID, nodeID string) error {
+	result := database.DB.Model(&models.TaskLease{}).
+		Where("task_id = ? AND node_id = ?", taskID, nodeID).
+		Updates(map[string]interface{}{
+			"expires_at": time.Now().Add(leaseTTL),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("续约失败：本节点已不再持有任务 %s 的租约", taskID)
+	}
+	return nil
+}
+
+// ReleaseLease 释放租约，任务正常结束/停止时调用，让其他节点无需等待TTL过期即可接管
+func (c *ClusterService) ReleaseLease(taskID, nodeID string) error {
+	return database.DB.Where("task_id = ? AND node_id = ?", taskID, nodeID).Delete(&models.TaskLease{}).Error
+}
+
+// Owner 返回taskID当前的租约持有者，租约不存在时返回nil
+func (c *ClusterService) Owner(taskID string) (*models.TaskLease, error) {
+	var lease models.TaskLease
+	err := database.DB.Where("task_id = ?", taskID).First(&lease).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lease, nil
+}
+
+// LiveNodes 返回当前心跳存活的节点列表，供管理页面展示集群成员
+func (c *ClusterService) LiveNodes() ([]models.WorkerNode, error) {
+	return c.node.LiveNodes()
+}
+
+// ForwardControl 把taskID的action（pause/stop）转发给owner节点的内部控制接口；
+// owner节点暴露的/internal路由不做JWT鉴权，改用X-Internal-Secret共享密钥鉴权，只供集群内部节点互相调用
+func (c *ClusterService) ForwardControl(owner models.WorkerNode, taskID, action string) error {
+	url := fmt.Sprintf("http://%s:%d/internal/tasks/%s/%s", owner.IP, owner.Port, taskID, action)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(nil))
+	if err != nil {
+		return fmt.Errorf("构造转发%s请求失败: %w", action, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Internal-Secret", config.GlobalConfig.Cluster.InternalSecret)
+
+	client := &http.Client{Timeout: forwardControlTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("转发%s请求到节点%s(%s:%d)失败: %w", action, owner.NodeID, owner.IP, owner.Port, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("节点%s(%s:%d)处理%s请求失败，状态码%d", owner.NodeID, owner.IP, owner.Port, action, resp.StatusCode)
+	}
+	return nil
+}
+
+// ForwardIfRemoteOwner 在本节点没有该taskID的本地调度状态时，查询租约owner并把action转发过去；
+// handled=true表示任务由其他存活节点持有、已经转发（err为转发结果），handled=false表示
+// 调用方应该按本地任务处理（租约不存在/已过期，或owner就是本节点）
+func (c *ClusterService) ForwardIfRemoteOwner(taskID, action string) (handled bool, err error) {
+	lease, err := c.Owner(taskID)
+	if err != nil {
+		return false, err
+	}
+	if lease == nil || lease.NodeID == c.node.NodeID() {
+		return false, nil
+	}
+
+	owner, err := c.nodeByID(lease.NodeID)
+	if err != nil {
+		return false, err
+	}
+	if owner == nil || !c.node.IsLive(owner.NodeID) {
+		// owner节点已失联，租约会在TTL后自动过期，放行给本节点按本地状态处理
+		return false, nil
+	}
+
+	return true, c.ForwardControl(*owner, taskID, action)
+}
+
+// nodeByID 按nodeID查询节点心跳记录，用于转发前解析owner的IP/端口
+func (c *ClusterService) nodeByID(nodeID string) (*models.WorkerNode, error) {
+	var node models.WorkerNode
+	err := database.DB.Where("node_id = ?", nodeID).First(&node).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+const (
+	poolMaxOpenConns       = 10               // 单个连接池允许的最大打开连接数
+	poolMaxIdleConns       = 5                // 单个连接池保留的最大空闲连接数
+	poolConnMaxLifetime    = time.Hour        // 单个连接的最大存活时间，超过后被关闭重建
+	poolConnMaxIdleTime    = 10 * time.Minute // 单个连接的最大空闲时间
+	poolIdleEvictTTL       = 30 * time.Minute // 引用计数归零后，连接池还保留多久才被整体回收
+	poolEvictSweepInterval = 5 * time.Minute  // 后台回收协程的扫描间隔
+)
+
+// pooledConn 连接池中一条(host, port, username, database)连接的句柄及引用计数
+type pooledConn struct {
+	db        *sql.DB
+	refCount  int
+	idleSince time.Time // refCount归零的时刻，用于TTL淘汰；refCount>0时为零值
+}
+
+// ConnectionPool 按(host, port, username, database)缓存MySQL连接池，避免元数据查询/写入/连接测试
+// 每次都重新sql.Open。不缓存凭据：调用方每次Borrow仍需解密并传入明文密码（仅在首次建池时使用）。
+// 同一数据源的同一个库在并发场景下只会建立一个底层连接池，
+// 通过引用计数支持多个借用方并存，计数归零后由后台协程按TTL惰性回收。
+type ConnectionPool struct {
+	mu    sync.Mutex
+	conns map[string]*pooledConn
+}
+
+var (
+	connectionPoolInstance *ConnectionPool
+	connectionPoolOnce     sync.Once
+)
+
+// NewConnectionPool 获取连接池单例
+func NewConnectionPool() *ConnectionPool {
+	connectionPoolOnce.Do(func() {
+		connectionPoolInstance = &ConnectionPool{conns: make(map[string]*pooledConn)}
+		go connectionPoolInstance.evictLoop()
+	})
+	return connectionPoolInstance
+}
+
+// poolKey 连接池缓存键：同一服务器同一账号同一库复用同一个底层连接池
+func poolKey(host string, port int, username, database string) string {
+	return fmt.Sprintf("%s:%d:%s:%s", host, port, username, database)
+}
+
+// poolKeyPrefix 按服务器+账号前缀匹配，用于Invalidate时清理该账号下全部库的连接池
+func poolKeyPrefix(host string, port int, username string) string {
+	return fmt.Sprintf("%s:%d:%s:", host, port, username)
+}
+
+// Borrow 借用(host, port, username, database)对应的连接池，首次借用时建立连接并应用连接池参数；
+// 返回的release函数必须在使用完毕后调用以归还引用计数，通常配合defer使用
+func (p *ConnectionPool) Borrow(host string, port int, username, password, database string) (*sql.DB, func(), error) {
+	key := poolKey(host, port, username, database)
+
+	p.mu.Lock()
+	if pc, ok := p.conns[key]; ok {
+		pc.refCount++
+		pc.idleSince = time.Time{}
+		p.mu.Unlock()
+		return pc.db, p.releaseFunc(key), nil
+	}
+	p.mu.Unlock()
+
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local", username, password, host, port, database)
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("连接失败: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("数据库连接测试失败: %w", err)
+	}
+	db.SetMaxOpenConns(poolMaxOpenConns)
+	db.SetMaxIdleConns(poolMaxIdleConns)
+	db.SetConnMaxLifetime(poolConnMaxLifetime)
+	db.SetConnMaxIdleTime(poolConnMaxIdleTime)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// 双重检查：借用期间可能已有并发请求建好了同一个连接池
+	if pc, ok := p.conns[key]; ok {
+		pc.refCount++
+		pc.idleSince = time.Time{}
+		db.Close()
+		return pc.db, p.releaseFunc(key), nil
+	}
+	p.conns[key] = &pooledConn{db: db, refCount: 1}
+	return db, p.releaseFunc(key), nil
+}
+
+// releaseFunc 构造归还某个key引用计数的闭包
+func (p *ConnectionPool) releaseFunc(key string) func() {
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		pc, ok := p.conns[key]
+		if !ok {
+			return
+		}
+		pc.refCount--
+		if pc.refCount <= 0 {
+			pc.refCount = 0
+			pc.idleSince = time.Now()
+		}
+	}
+}
+
+// Stats 返回(host, port, username, database)对应连接池的db.Stats()，该连接池从未建立过时返回false
+func (p *ConnectionPool) Stats(host string, port int, username, database string) (sql.DBStats, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pc, ok := p.conns[poolKey(host, port, username, database)]
+	if !ok {
+		return sql.DBStats{}, false
+	}
+	return pc.db.Stats(), true
+}
+
+// Invalidate 关闭并移除某个服务器+账号下全部库的连接池，供数据源的凭据变更（Update）或删除（Delete）时调用，
+// 避免借用方继续用到已失效的密码或已不存在的数据源
+func (p *ConnectionPool) Invalidate(host string, port int, username string) {
+	prefix := poolKeyPrefix(host, port, username)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, pc := range p.conns {
+		if strings.HasPrefix(key, prefix) {
+			pc.db.Close()
+			delete(p.conns, key)
+		}
+	}
+}
+
+// evictLoop 定期回收引用计数为0且空闲超过TTL的连接池
+func (p *ConnectionPool) evictLoop() {
+	ticker := time.NewTicker(poolEvictSweepInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		p.mu.Lock()
+		now := time.Now()
+		for key, pc := range p.conns {
+			if pc.refCount == 0 && !pc.idleSince.IsZero() && now.Sub(pc.idleSince) > poolIdleEvictTTL {
+				pc.db.Close()
+				delete(p.conns, key)
+			}
+		}
+		p.mu.Unlock()
+	}
+}
@@ -0,0 +1,339 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"datatrace/config"
+	"datatrace/models"
+
+	_ "github.com/denisenkom/go-mssqldb"
+	_ "github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// previewRowLimit MongoDB/Elasticsearch预览接口返回的最大样例行数；关系型数据源的行数上限
+// 由QueryTranslator按config.Query.MaxPreviewLimit截断
+const previewRowLimit = 100
+
+// PreviewRequest 即席预览请求。关系型数据源填Table/Select/Where/OrderBy/Limit/Offset，
+// 由QueryTranslator编译为参数化SQL，不接受任意SQL表达式；Elasticsearch/MongoDB用DSL，
+// 外加各自所需的Index/Collection
+type PreviewRequest struct {
+	Table      string                 `json:"table"`      // 关系型数据源：要查询的表名
+	Select     []string               `json:"select"`     // 关系型数据源：要查询的列，为空表示SELECT *
+	Where      map[string]interface{} `json:"where"`      // 关系型数据源：等值过滤条件，key为列名，不支持表达式
+	OrderBy    []string               `json:"order_by"`   // 关系型数据源：排序列
+	Limit      int                    `json:"limit"`      // 关系型数据源：返回行数，超过配置的max_preview_limit会被截断
+	Offset     int                    `json:"offset"`     // 关系型数据源：跳过的行数
+	Collection string                 `json:"collection"` // MongoDB专用：目标集合
+	Index      string                 `json:"index"`      // Elasticsearch专用：目标索引
+	DSL        json.RawMessage        `json:"dsl"`        // Elasticsearch的_search请求体 / MongoDB的find过滤条件，均为JSON
+}
+
+// toQueryRequest 提取关系型数据源部分的字段，交给QueryTranslator编译
+func (r *PreviewRequest) toQueryRequest() *QueryRequest {
+	return &QueryRequest{
+		Table:   r.Table,
+		Select:  r.Select,
+		Where:   r.Where,
+		OrderBy: r.OrderBy,
+		Limit:   r.Limit,
+		Offset:  r.Offset,
+	}
+}
+
+// PreviewResult 预览结果
+type PreviewResult struct {
+	Columns []string                 `json:"columns,omitempty"`
+	Rows    []map[string]interface{} `json:"rows"`
+}
+
+// maxPreviewLimit 返回配置的预览行数上限，未配置时退回previewRowLimit
+func maxPreviewLimit() int {
+	if config.GlobalConfig != nil && config.GlobalConfig.Query.MaxPreviewLimit > 0 {
+		return config.GlobalConfig.Query.MaxPreviewLimit
+	}
+	return previewRowLimit
+}
+
+// Preview 按数据源ID执行一次只读的即席查询，返回样例数据，供配置同步任务前预览表结构/DSL
+func (s *DataSourceService) Preview(id string, req *PreviewRequest) (*PreviewResult, error) {
+	ds, err := s.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("数据源不存在")
+	}
+
+	password, err := s.crypto.Decrypt(ds.Password)
+	if err != nil {
+		return nil, fmt.Errorf("密码解密失败")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return previewByType(ctx, ds, password, req)
+}
+
+// PreviewStream 与Preview等价，但逐行把结果推入rowCh，供SSE接口边查边推，避免整批结果缓冲在内存里；
+// ctx被取消（如客户端断开连接）会中止底层的db.QueryContext
+func (s *DataSourceService) PreviewStream(ctx context.Context, id string, req *PreviewRequest, rowCh chan<- map[string]interface{}) ([]string, error) {
+	ds, err := s.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("数据源不存在")
+	}
+
+	password, err := s.crypto.Decrypt(ds.Password)
+	if err != nil {
+		return nil, fmt.Errorf("密码解密失败")
+	}
+
+	switch ds.Type {
+	case "mysql", "postgresql", "sqlserver":
+		db, query, args, err := openRelationalPreview(ds, password, req)
+		if err != nil {
+			return nil, err
+		}
+		defer db.Close()
+		return streamQueryRows(ctx, db, query, args, rowCh)
+	default:
+		// MongoDB/Elasticsearch没有现成的可复用取消点，退化为整批取回后逐行推送，仍然尊重ctx取消
+		result, err := previewByType(ctx, ds, password, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range result.Rows {
+			select {
+			case rowCh <- row:
+			case <-ctx.Done():
+				return result.Columns, ctx.Err()
+			}
+		}
+		return result.Columns, nil
+	}
+}
+
+// previewByType 按数据源类型分发到具体实现
+func previewByType(ctx context.Context, ds *models.DataSource, password string, req *PreviewRequest) (*PreviewResult, error) {
+	switch ds.Type {
+	case "mysql", "postgresql", "sqlserver":
+		db, query, args, err := openRelationalPreview(ds, password, req)
+		if err != nil {
+			return nil, err
+		}
+		defer db.Close()
+		return queryPreviewRows(ctx, db, query, args)
+	case "mongodb":
+		return previewMongoDB(ctx, ds, password, req)
+	case "elasticsearch":
+		return previewElasticsearch(ctx, ds, password, req)
+	default:
+		return nil, fmt.Errorf("数据源类型 %s 不支持预览", ds.Type)
+	}
+}
+
+// openRelationalPreview 用QueryTranslator校验并编译req为参数化SQL，并打开对应方言的数据库连接
+func openRelationalPreview(ds *models.DataSource, password string, req *PreviewRequest) (*sql.DB, string, []interface{}, error) {
+	translator := NewQueryTranslator(ds.Type, maxPreviewLimit())
+	query, args, err := translator.Build(req.toQueryRequest())
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var driver, dsn string
+	switch ds.Type {
+	case "mysql":
+		driver = "mysql"
+		dsn = fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local&timeout=5s",
+			ds.Username, password, ds.Host, ds.Port, ds.DatabaseName)
+	case "postgresql":
+		driver = "postgres"
+		dsn = fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable connect_timeout=5",
+			ds.Host, ds.Port, ds.Username, password, ds.DatabaseName)
+	case "sqlserver":
+		driver = "sqlserver"
+		dsn = fmt.Sprintf("sqlserver://%s:%s@%s:%d?database=%s&connection+timeout=5",
+			ds.Username, password, ds.Host, ds.Port, ds.DatabaseName)
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("连接失败: %w", err)
+	}
+	return db, query, args, nil
+}
+
+// queryPreviewRows 执行参数化查询并整批取回结果，供非流式的Preview复用
+func queryPreviewRows(ctx context.Context, db *sql.DB, query string, args []interface{}) (*PreviewResult, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("获取列名失败: %w", err)
+	}
+
+	result := &PreviewResult{Columns: columns, Rows: []map[string]interface{}{}}
+	for rows.Next() {
+		row, err := scanPreviewRow(rows, columns)
+		if err != nil {
+			return nil, err
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, rows.Err()
+}
+
+// streamQueryRows 执行参数化查询并逐行推入rowCh，ctx取消（客户端断开）会中止扫描
+func streamQueryRows(ctx context.Context, db *sql.DB, query string, args []interface{}, rowCh chan<- map[string]interface{}) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("获取列名失败: %w", err)
+	}
+
+	for rows.Next() {
+		row, err := scanPreviewRow(rows, columns)
+		if err != nil {
+			return columns, err
+		}
+		select {
+		case rowCh <- row:
+		case <-ctx.Done():
+			return columns, ctx.Err()
+		}
+	}
+	return columns, rows.Err()
+}
+
+// scanPreviewRow 扫描当前行为列名到值的映射，[]byte统一转为字符串，便于JSON序列化
+func scanPreviewRow(rows *sql.Rows, columns []string) (map[string]interface{}, error) {
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+	if err := rows.Scan(valuePtrs...); err != nil {
+		return nil, fmt.Errorf("扫描行数据失败: %w", err)
+	}
+
+	row := make(map[string]interface{})
+	for i, col := range columns {
+		if b, ok := values[i].([]byte); ok {
+			row[col] = string(b)
+		} else {
+			row[col] = values[i]
+		}
+	}
+	return row, nil
+}
+
+// previewMongoDB 预览MongoDB数据源的即席find查询结果，DSL为JSON格式的过滤条件，留空表示不过滤
+func previewMongoDB(ctx context.Context, ds *models.DataSource, password string, req *PreviewRequest) (*PreviewResult, error) {
+	if req.Collection == "" {
+		return nil, fmt.Errorf("collection不能为空")
+	}
+
+	filter := bson.M{}
+	if len(req.DSL) > 0 {
+		if err := bson.UnmarshalExtJSON(req.DSL, true, &filter); err != nil {
+			return nil, fmt.Errorf("dsl解析失败: %w", err)
+		}
+	}
+
+	uri := fmt.Sprintf("mongodb://%s:%s@%s:%d/?authSource=admin", ds.Username, password, ds.Host, ds.Port)
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	defer client.Disconnect(ctx)
+
+	cursor, err := client.Database(ds.DatabaseName).Collection(req.Collection).
+		Find(ctx, filter, options.Find().SetLimit(previewRowLimit))
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	rows := []map[string]interface{}{}
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("解析文档失败: %w", err)
+		}
+		rows = append(rows, doc)
+	}
+	return &PreviewResult{Rows: rows}, nil
+}
+
+// previewElasticsearch 预览Elasticsearch数据源的即席DSL查询结果，DSL为_search请求体，留空则返回前N条
+func previewElasticsearch(ctx context.Context, ds *models.DataSource, password string, req *PreviewRequest) (*PreviewResult, error) {
+	if req.Index == "" {
+		return nil, fmt.Errorf("index不能为空")
+	}
+
+	dsl := req.DSL
+	if len(dsl) == 0 {
+		dsl = []byte(fmt.Sprintf(`{"size":%d}`, previewRowLimit))
+	}
+
+	url := fmt.Sprintf("http://%s:%d/%s/_search", ds.Host, ds.Port, req.Index)
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(dsl))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.SetBasicAuth(ds.Username, password)
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("查询失败: HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	var parsed struct {
+		Hits struct {
+			Hits []struct {
+				Source map[string]interface{} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	rows := make([]map[string]interface{}, 0, len(parsed.Hits.Hits))
+	for _, hit := range parsed.Hits.Hits {
+		if len(rows) >= previewRowLimit {
+			break
+		}
+		rows = append(rows, hit.Source)
+	}
+	return &PreviewResult{Rows: rows}, nil
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"database/sql"
 	"datatrace/database"
 	"datatrace/models"
 	"datatrace/utils"
@@ -26,11 +27,12 @@ func NewDataSourceService() *DataSourceService {
 type CreateDataSourceRequest struct {
 	Name         string `json:"name" binding:"required"`
 	Type         string `json:"type" binding:"required"`
-	Host         string `json:"host" binding:"required"`
-	Port         int    `json:"port" binding:"required"`
-	Username     string `json:"username" binding:"required"`
-	Password     string `json:"password" binding:"required"`
+	Host         string `json:"host" binding:"required"` // SQLite 数据源复用该字段存储数据库文件路径
+	Port         int    `json:"port"`                    // SQLite 无端口概念，可不传
+	Username     string `json:"username"`                // SQLite 无账号体系，可不传
+	Password     string `json:"password"`
 	DatabaseName string `json:"database_name"`
+	UseSSL       bool   `json:"use_ssl"` // Elasticsearch专用：是否使用HTTPS协议
 }
 
 // Create 创建数据源
@@ -63,6 +65,7 @@ func (s *DataSourceService) Create(req *CreateDataSourceRequest) (*models.DataSo
 		Username:     req.Username,
 		Password:     encryptedPassword,
 		DatabaseName: req.DatabaseName,
+		UseSSL:       req.UseSSL,
 	}
 
 	if err := database.DB.Create(ds).Error; err != nil {
@@ -90,6 +93,19 @@ func (s *DataSourceService) GetByID(id string) (*models.DataSource, error) {
 	return &ds, nil
 }
 
+// PoolStats 返回数据源对应连接池的db.Stats()；非MySQL数据源或尚未借用过连接时found为false
+func (s *DataSourceService) PoolStats(id string) (stats sql.DBStats, found bool, err error) {
+	ds, err := s.GetByID(id)
+	if err != nil {
+		return sql.DBStats{}, false, fmt.Errorf("数据源不存在")
+	}
+	if ds.Type != "mysql" {
+		return sql.DBStats{}, false, nil
+	}
+	stats, found = NewConnectionPool().Stats(ds.Host, ds.Port, ds.Username, ds.DatabaseName)
+	return stats, found, nil
+}
+
 // Update 更新数据源
 func (s *DataSourceService) Update(id string, req *CreateDataSourceRequest) (*models.DataSource, error) {
 	// 查询数据源
@@ -112,6 +128,9 @@ func (s *DataSourceService) Update(id string, req *CreateDataSourceRequest) (*mo
 		return nil, fmt.Errorf("数据源名称已存在")
 	}
 
+	// 更新前记录旧连接信息，供保存后失效旧连接池
+	oldHost, oldPort, oldUsername := ds.Host, ds.Port, ds.Username
+
 	// 更新字段
 	ds.Name = req.Name
 	ds.Type = req.Type
@@ -119,6 +138,7 @@ func (s *DataSourceService) Update(id string, req *CreateDataSourceRequest) (*mo
 	ds.Port = req.Port
 	ds.Username = req.Username
 	ds.DatabaseName = req.DatabaseName
+	ds.UseSSL = req.UseSSL
 
 	// 如果提供了新密码，重新加密
 	if req.Password != "" {
@@ -133,11 +153,19 @@ func (s *DataSourceService) Update(id string, req *CreateDataSourceRequest) (*mo
 		return nil, fmt.Errorf("更新失败: %w", err)
 	}
 
+	// 主机/账号/密码可能已变更，失效旧连接池，避免借用方继续用到过期凭据
+	NewConnectionPool().Invalidate(oldHost, oldPort, oldUsername)
+
 	return ds, nil
 }
 
 // Delete 删除数据源
 func (s *DataSourceService) Delete(id string) error {
+	ds, err := s.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("数据源不存在")
+	}
+
 	// 检查是否被任务使用
 	var count int64
 	database.DB.Model(&models.SyncTask{}).
@@ -151,6 +179,8 @@ func (s *DataSourceService) Delete(id string) error {
 		return fmt.Errorf("删除失败: %w", err)
 	}
 
+	NewConnectionPool().Invalidate(ds.Host, ds.Port, ds.Username)
+
 	return nil
 }
 
@@ -159,16 +189,22 @@ func (s *DataSourceService) validate(req *CreateDataSourceRequest) error {
 	if req.Name == "" {
 		return fmt.Errorf("数据源名称不能为空")
 	}
-	if req.Type != "mysql" && req.Type != "elasticsearch" {
+	switch req.Type {
+	case "mysql", "postgresql", "sqlserver", "mongodb", "elasticsearch", "sqlite":
+	default:
 		return fmt.Errorf("数据源类型无效")
 	}
 	if req.Host == "" {
+		// SQLite 为文件型数据库，Host 字段复用为数据库文件路径
+		if req.Type == "sqlite" {
+			return fmt.Errorf("数据库文件路径不能为空")
+		}
 		return fmt.Errorf("主机地址不能为空")
 	}
-	if req.Port <= 0 || req.Port > 65535 {
+	if req.Type != "sqlite" && (req.Port <= 0 || req.Port > 65535) {
 		return fmt.Errorf("端口号无效")
 	}
-	if req.Username == "" {
+	if req.Type != "sqlite" && req.Username == "" {
 		return fmt.Errorf("用户名不能为空")
 	}
 	// MySQL 数据库名称改为可选，连接时可以不指定数据库
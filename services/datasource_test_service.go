@@ -7,15 +7,21 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+
+	_ "github.com/denisenkom/go-mssqldb"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // TestConnectionRequest 测试连接请求
 type TestConnectionRequest struct {
 	Type         string `json:"type" binding:"required"`
-	Host         string `json:"host" binding:"required"`
-	Port         int    `json:"port" binding:"required"`
-	Username     string `json:"username" binding:"required"`
-	Password     string `json:"password" binding:"required"`
+	Host         string `json:"host" binding:"required"` // SQLite 数据源复用该字段存储数据库文件路径
+	Port         int    `json:"port"`
+	Username     string `json:"username"`
+	Password     string `json:"password"`
 	DatabaseName string `json:"database_name"`
 	UseSSL       bool   `json:"use_ssl"` // 是否使用SSL/HTTPS
 }
@@ -32,6 +38,14 @@ func (s *DataSourceService) TestConnection(req *TestConnectionRequest) (*TestCon
 	switch req.Type {
 	case "mysql":
 		return s.testMySQLConnection(req)
+	case "postgresql":
+		return s.testPostgreSQLConnection(req)
+	case "sqlserver":
+		return s.testSQLServerConnection(req)
+	case "mongodb":
+		return s.testMongoDBConnection(req)
+	case "sqlite":
+		return s.testSQLiteConnection(req)
 	case "elasticsearch":
 		return s.testElasticsearchConnection(req)
 	default:
@@ -54,6 +68,11 @@ func (s *DataSourceService) TestConnectionByID(id string) (*TestConnectionRespon
 		}, nil
 	}
 
+	// MySQL 数据源从连接池借用连接，避免重复sql.Open；其余类型走原有的即连即测
+	if ds.Type == "mysql" {
+		return s.testMySQLConnectionPooled(ds.Host, ds.Port, ds.Username, password, ds.DatabaseName)
+	}
+
 	req := &TestConnectionRequest{
 		Type:         ds.Type,
 		Host:         ds.Host,
@@ -61,11 +80,31 @@ func (s *DataSourceService) TestConnectionByID(id string) (*TestConnectionRespon
 		Username:     ds.Username,
 		Password:     password,
 		DatabaseName: ds.DatabaseName,
+		UseSSL:       ds.UseSSL,
 	}
 
 	return s.TestConnection(req)
 }
 
+// testMySQLConnectionPooled 借用ConnectionPool中的连接测试MySQL数据源，借用方归还后连接仍留在池中供复用
+func (s *DataSourceService) testMySQLConnectionPooled(host string, port int, username, password, database string) (*TestConnectionResponse, error) {
+	db, release, err := NewConnectionPool().Borrow(host, port, username, password, database)
+	if err != nil {
+		return &TestConnectionResponse{Success: false, Message: fmt.Sprintf("连接失败: %v", err)}, nil
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version); err != nil {
+		version = "未知"
+	}
+
+	return &TestConnectionResponse{Success: true, Version: version, Message: "连接成功"}, nil
+}
+
 // testMySQLConnection 测试 MySQL 连接
 func (s *DataSourceService) testMySQLConnection(req *TestConnectionRequest) (*TestConnectionResponse, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/", req.Username, req.Password, req.Host, req.Port)
@@ -106,6 +145,114 @@ func (s *DataSourceService) testMySQLConnection(req *TestConnectionRequest) (*Te
 	}, nil
 }
 
+// testPostgreSQLConnection 测试 PostgreSQL 连接
+func (s *DataSourceService) testPostgreSQLConnection(req *TestConnectionRequest) (*TestConnectionResponse, error) {
+	dbname := req.DatabaseName
+	if dbname == "" {
+		dbname = "postgres"
+	}
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable connect_timeout=5",
+		req.Host, req.Port, req.Username, req.Password, dbname)
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return &TestConnectionResponse{Success: false, Message: fmt.Sprintf("连接失败: %v", err)}, nil
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		return &TestConnectionResponse{Success: false, Message: fmt.Sprintf("连接失败: %v", err)}, nil
+	}
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SHOW server_version").Scan(&version); err != nil {
+		version = "未知"
+	}
+
+	return &TestConnectionResponse{Success: true, Version: version, Message: "连接成功"}, nil
+}
+
+// testSQLServerConnection 测试 SQL Server 连接
+func (s *DataSourceService) testSQLServerConnection(req *TestConnectionRequest) (*TestConnectionResponse, error) {
+	dsn := fmt.Sprintf("sqlserver://%s:%s@%s:%d?connection+timeout=5", req.Username, req.Password, req.Host, req.Port)
+	if req.DatabaseName != "" {
+		dsn += "&database=" + req.DatabaseName
+	}
+
+	db, err := sql.Open("sqlserver", dsn)
+	if err != nil {
+		return &TestConnectionResponse{Success: false, Message: fmt.Sprintf("连接失败: %v", err)}, nil
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		return &TestConnectionResponse{Success: false, Message: fmt.Sprintf("连接失败: %v", err)}, nil
+	}
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT @@VERSION").Scan(&version); err != nil {
+		version = "未知"
+	}
+
+	return &TestConnectionResponse{Success: true, Version: version, Message: "连接成功"}, nil
+}
+
+// testMongoDBConnection 测试 MongoDB 连接
+func (s *DataSourceService) testMongoDBConnection(req *TestConnectionRequest) (*TestConnectionResponse, error) {
+	uri := fmt.Sprintf("mongodb://%s:%s@%s:%d/?authSource=admin", req.Username, req.Password, req.Host, req.Port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return &TestConnectionResponse{Success: false, Message: fmt.Sprintf("连接失败: %v", err)}, nil
+	}
+	defer client.Disconnect(ctx)
+
+	if err := client.Ping(ctx, nil); err != nil {
+		return &TestConnectionResponse{Success: false, Message: fmt.Sprintf("连接失败: %v", err)}, nil
+	}
+
+	var result struct {
+		Version string `bson:"version"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, map[string]interface{}{"buildInfo": 1}).Decode(&result); err != nil {
+		result.Version = "未知"
+	}
+
+	return &TestConnectionResponse{Success: true, Version: result.Version, Message: "连接成功"}, nil
+}
+
+// testSQLiteConnection 测试 SQLite 连接；Host 字段复用为数据库文件路径
+func (s *DataSourceService) testSQLiteConnection(req *TestConnectionRequest) (*TestConnectionResponse, error) {
+	db, err := sql.Open("sqlite3", req.Host)
+	if err != nil {
+		return &TestConnectionResponse{Success: false, Message: fmt.Sprintf("连接失败: %v", err)}, nil
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		return &TestConnectionResponse{Success: false, Message: fmt.Sprintf("连接失败: %v", err)}, nil
+	}
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT sqlite_version()").Scan(&version); err != nil {
+		version = "未知"
+	}
+
+	return &TestConnectionResponse{Success: true, Version: version, Message: "连接成功"}, nil
+}
+
 // testElasticsearchConnection 测试 Elasticsearch 连接
 func (s *DataSourceService) testElasticsearchConnection(req *TestConnectionRequest) (*TestConnectionResponse, error) {
 	// 根据 UseSSL 选择协议
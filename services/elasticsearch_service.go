@@ -0,0 +1,141 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/elastic/go-elasticsearch/v7"
+)
+
+// elasticsearchDefaultDatabase Elasticsearch集群级别没有独立于索引的"库"概念，
+// 元数据浏览时用这个固定名字代表整个集群，与SQLite用文件名代表唯一"库"是同一种处理方式
+const elasticsearchDefaultDatabase = "default"
+
+// ElasticsearchMetadataService Elasticsearch 元数据查询服务；索引对应关系型数据源的表
+type ElasticsearchMetadataService struct{}
+
+// NewElasticsearchMetadataService 创建 Elasticsearch 元数据服务
+func NewElasticsearchMetadataService() *ElasticsearchMetadataService {
+	return &ElasticsearchMetadataService{}
+}
+
+// newClient 建立Elasticsearch客户端；MetadataProvider接口不携带UseSSL，浏览场景固定走HTTP，
+// 需要HTTPS的集群请改用数据源详情页的"测试连接"（走TestConnectionRequest.UseSSL）
+func (s *ElasticsearchMetadataService) newClient(host string, port int, username, password string) (*elasticsearch.Client, error) {
+	cfg := elasticsearch.Config{
+		Addresses: []string{fmt.Sprintf("http://%s:%d", host, port)},
+		Username:  username,
+		Password:  password,
+	}
+	return elasticsearch.NewClient(cfg)
+}
+
+// GetDatabases 集群没有多库概念，返回固定的单一"库"，TableCount为索引总数
+func (s *ElasticsearchMetadataService) GetDatabases(host string, port int, username, password string) ([]DatabaseInfo, error) {
+	indices, err := s.listIndices(host, port, username, password)
+	if err != nil {
+		return nil, err
+	}
+	return []DatabaseInfo{{Name: elasticsearchDefaultDatabase, TableCount: len(indices)}}, nil
+}
+
+// GetSchemas Elasticsearch不存在独立于索引的模式概念，返回空列表
+func (s *ElasticsearchMetadataService) GetSchemas(host string, port int, username, password, database string) ([]string, error) {
+	return []string{}, nil
+}
+
+// GetTables 获取集群中的索引列表，映射为关系型数据源的表
+func (s *ElasticsearchMetadataService) GetTables(host string, port int, username, password, database string) ([]TableInfo, error) {
+	names, err := s.listIndices(host, port, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]TableInfo, 0, len(names))
+	for _, name := range names {
+		tables = append(tables, TableInfo{Name: name})
+	}
+	return tables, nil
+}
+
+// listIndices 通过_cat/indices列出用户索引，过滤掉以.开头的系统索引
+func (s *ElasticsearchMetadataService) listIndices(host string, port int, username, password string) ([]string, error) {
+	client, err := s.newClient(host, port, username, password)
+	if err != nil {
+		return nil, fmt.Errorf("创建客户端失败: %w", err)
+	}
+
+	res, err := client.Cat.Indices(client.Cat.Indices.WithFormat("json"))
+	if err != nil {
+		return nil, fmt.Errorf("查询索引列表失败: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("查询索引列表失败: %s", res.Status())
+	}
+
+	var rows []struct {
+		Index string `json:"index"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("解析索引列表失败: %w", err)
+	}
+
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if len(row.Index) > 0 && row.Index[0] == '.' {
+			continue
+		}
+		names = append(names, row.Index)
+	}
+	return names, nil
+}
+
+// GetColumns 获取索引mapping中的字段列表；Elasticsearch无主键概念，IsPK始终为false
+func (s *ElasticsearchMetadataService) GetColumns(host string, port int, username, password, database, table string) ([]ColumnInfo, error) {
+	client, err := s.newClient(host, port, username, password)
+	if err != nil {
+		return nil, fmt.Errorf("创建客户端失败: %w", err)
+	}
+
+	res, err := client.Indices.GetMapping(client.Indices.GetMapping.WithIndex(table))
+	if err != nil {
+		return nil, fmt.Errorf("查询mapping失败: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("查询mapping失败: %s", res.Status())
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取mapping失败: %w", err)
+	}
+
+	var mappings map[string]struct {
+		Mappings struct {
+			Properties map[string]struct {
+				Type string `json:"type"`
+			} `json:"properties"`
+		} `json:"mappings"`
+	}
+	if err := json.Unmarshal(body, &mappings); err != nil {
+		return nil, fmt.Errorf("解析mapping失败: %w", err)
+	}
+
+	indexMapping, ok := mappings[table]
+	if !ok {
+		return []ColumnInfo{}, nil
+	}
+
+	columns := make([]ColumnInfo, 0, len(indexMapping.Mappings.Properties))
+	for name, prop := range indexMapping.Mappings.Properties {
+		columns = append(columns, ColumnInfo{
+			Name:     name,
+			DataType: prop.Type,
+			Nullable: true,
+		})
+	}
+	return columns, nil
+}
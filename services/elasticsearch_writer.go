@@ -0,0 +1,158 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v7"
+)
+
+// ElasticsearchWriter Elasticsearch数据写入器；表映射为索引，行映射为JSON文档，批量写入走_bulk接口
+type ElasticsearchWriter struct {
+	client *elasticsearch.Client
+	index  string
+}
+
+// NewElasticsearchWriter 创建Elasticsearch写入器
+func NewElasticsearchWriter(cfg WriterConfig) (*ElasticsearchWriter, error) {
+	if err := ValidateTableName(cfg.Table); err != nil {
+		return nil, fmt.Errorf("索引名校验失败: %w", err)
+	}
+
+	scheme := "http"
+	if cfg.UseSSL {
+		scheme = "https"
+	}
+
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{fmt.Sprintf("%s://%s:%d", scheme, cfg.Host, cfg.Port)},
+		Username:  cfg.Username,
+		Password:  cfg.Password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建客户端失败: %w", err)
+	}
+
+	return &ElasticsearchWriter{client: client, index: cfg.Table}, nil
+}
+
+// WriteBatch 将行记录拼装为NDJSON请求体后调用_bulk接口批量索引
+func (w *ElasticsearchWriter) WriteBatch(records []map[string]interface{}) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	for _, record := range records {
+		action, err := json.Marshal(map[string]interface{}{
+			"index": map[string]interface{}{"_index": w.index},
+		})
+		if err != nil {
+			return fmt.Errorf("构造bulk元数据失败: %w", err)
+		}
+		doc, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("序列化文档失败: %w", err)
+		}
+		body.Write(action)
+		body.WriteByte('\n')
+		body.Write(doc)
+		body.WriteByte('\n')
+	}
+
+	res, err := w.client.Bulk(bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return fmt.Errorf("批量写入失败: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("批量写入失败: %s", res.Status())
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err == nil && result.Errors {
+		return fmt.Errorf("批量写入部分文档失败，详见ES响应")
+	}
+	return nil
+}
+
+// CreateTableLike 按标准化列信息创建索引及其mapping
+func (w *ElasticsearchWriter) CreateTableLike(columns []ColumnInfo) error {
+	properties := make(map[string]interface{}, len(columns))
+	for _, col := range columns {
+		properties[col.Name] = map[string]interface{}{"type": elasticsearchFieldType(col)}
+	}
+
+	mapping := map[string]interface{}{
+		"mappings": map[string]interface{}{"properties": properties},
+	}
+	body, err := json.Marshal(mapping)
+	if err != nil {
+		return fmt.Errorf("构造mapping失败: %w", err)
+	}
+
+	res, err := w.client.Indices.Create(w.index, w.client.Indices.Create.WithBody(bytes.NewReader(body)))
+	if err != nil {
+		return fmt.Errorf("创建索引失败: %w", err)
+	}
+	defer res.Body.Close()
+	// 索引已存在视为幂等成功，与其它方言"CREATE TABLE IF NOT EXISTS"的语义保持一致
+	if res.IsError() && !strings.Contains(res.String(), "resource_already_exists_exception") {
+		return fmt.Errorf("创建索引失败: %s", res.Status())
+	}
+	return nil
+}
+
+// elasticsearchFieldType 将标准化逻辑类型翻译为Elasticsearch字段类型
+func elasticsearchFieldType(col ColumnInfo) string {
+	switch logicalType(col.DataType) {
+	case "bigint":
+		return "long"
+	case "decimal":
+		return "double"
+	case "bool":
+		return "boolean"
+	case "datetime":
+		return "date"
+	case "text":
+		return "text"
+	default:
+		return "keyword"
+	}
+}
+
+// TruncateTable 通过delete_by_query清空索引中的所有文档
+func (w *ElasticsearchWriter) TruncateTable() error {
+	query := bytes.NewReader([]byte(`{"query":{"match_all":{}}}`))
+	res, err := w.client.DeleteByQuery([]string{w.index}, query)
+	if err != nil {
+		return fmt.Errorf("清空索引失败: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("清空索引失败: %s", res.Status())
+	}
+	return nil
+}
+
+// DropTable 删除索引
+func (w *ElasticsearchWriter) DropTable() error {
+	res, err := w.client.Indices.Delete([]string{w.index})
+	if err != nil {
+		return fmt.Errorf("删除索引失败: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() && res.StatusCode != 404 {
+		return fmt.Errorf("删除索引失败: %s", res.Status())
+	}
+	return nil
+}
+
+// Close Elasticsearch客户端基于HTTP短连接池，无需显式释放
+func (w *ElasticsearchWriter) Close() error {
+	return nil
+}
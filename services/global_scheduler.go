@@ -0,0 +1,273 @@
+package services
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"datatrace/config"
+	"datatrace/models"
+)
+
+// globalItem 全局调度堆中的一项，携带所属任务的ctx以便worker据此感知该任务是否已被Pause/Stop取消
+type globalItem struct {
+	taskID   string
+	ctx      context.Context
+	unit     *models.TaskUnitRuntime
+	priority int
+	deadline *time.Time
+	seq      int64
+	done     func()
+}
+
+// globalItemHeap 排序规则：(priority ASC, deadline ASC, seq ASC)，未设置截止时间的排在有截止时间的之后
+type globalItemHeap []*globalItem
+
+func (h globalItemHeap) Len() int { return len(h) }
+
+func (h globalItemHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority < h[j].priority
+	}
+	di, dj := h[i].deadline, h[j].deadline
+	switch {
+	case di == nil && dj == nil:
+	case di == nil:
+		return false
+	case dj == nil:
+		return true
+	case !di.Equal(*dj):
+		return di.Before(*dj)
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h globalItemHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *globalItemHeap) Push(x interface{}) { *h = append(*h, x.(*globalItem)) }
+
+func (h *globalItemHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// GlobalScheduler 跨任务共享的调度器：全局worker池数量由scheduler.max_global_workers限制，
+// 所有正在运行任务的待处理单元放进同一个堆，按优先级出队；同时按"当前存在待处理/运行中单元的任务数"
+// 动态计算每个任务的公平份额(fair share)，单个任务在运行单元数达到份额前不能继续抢占全局worker，
+// 避免一个单元很多的大任务把其余任务饿死。任务自身的thread_count配置作为该任务并发数的上限，
+// 与公平份额取较小值
+type GlobalScheduler struct {
+	mu         sync.Mutex
+	heap       globalItemHeap
+	nextSeq    int64
+	maxWorkers int
+	running    map[string]int // taskID -> 当前正在处理的单元数
+	limits     map[string]int // taskID -> 该任务自身的并发上限（thread_count），0表示未设置/不限制
+	wake       chan struct{}
+	engine     *SyncEngine
+}
+
+var (
+	globalSchedulerInstance *GlobalScheduler
+	globalSchedulerOnce     sync.Once
+)
+
+// NewGlobalScheduler 获取全局调度器单例，首次调用时启动固定数量的worker goroutine
+func NewGlobalScheduler() *GlobalScheduler {
+	globalSchedulerOnce.Do(func() {
+		maxWorkers := config.GlobalConfig.Scheduler.MaxGlobalWorkers
+		if maxWorkers <= 0 {
+			maxWorkers = 16
+		}
+		globalSchedulerInstance = &GlobalScheduler{
+			maxWorkers: maxWorkers,
+			running:    make(map[string]int),
+			limits:     make(map[string]int),
+			wake:       make(chan struct{}, 1),
+			engine:     NewSyncEngine(),
+		}
+		for i := 0; i < maxWorkers; i++ {
+			go globalSchedulerInstance.runWorker(i)
+		}
+	})
+	return globalSchedulerInstance
+}
+
+// Submit 把taskID的一个待处理单元放入全局堆；limit<=0表示该任务不设置自身并发上限（只受公平份额约束）
+func (s *GlobalScheduler) Submit(taskID string, ctx context.Context, unit *models.TaskUnitRuntime, priority int, deadline *time.Time, limit int, done func()) {
+	s.mu.Lock()
+	if limit > 0 {
+		s.limits[taskID] = limit
+	}
+	s.nextSeq++
+	heap.Push(&s.heap, &globalItem{
+		taskID: taskID, ctx: ctx, unit: unit, priority: priority, deadline: deadline, seq: s.nextSeq, done: done,
+	})
+	s.mu.Unlock()
+	s.notify()
+}
+
+// CancelTask 从堆中移除taskID所有尚未被worker取出的单元，用于Pause/Stop时防止继续调度已取消任务的单元；
+// 被移除单元的done回调照常触发，避免调用方的WaitGroup.Wait永久阻塞
+func (s *GlobalScheduler) CancelTask(taskID string) {
+	s.mu.Lock()
+	kept := s.heap[:0]
+	var removed []*globalItem
+	for _, item := range s.heap {
+		if item.taskID == taskID {
+			removed = append(removed, item)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	s.heap = kept
+	heap.Init(&s.heap)
+	delete(s.limits, taskID)
+	s.mu.Unlock()
+
+	for _, item := range removed {
+		item.done()
+	}
+}
+
+// Rebuild 重新设置taskID在堆中所有尚未被取出单元的优先级/截止时间，正在运行的单元不受影响
+func (s *GlobalScheduler) Rebuild(taskID string, priority int, deadline *time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, item := range s.heap {
+		if item.taskID == taskID {
+			item.priority = priority
+			item.deadline = deadline
+		}
+	}
+	heap.Init(&s.heap)
+}
+
+// Reprioritize 单独调整taskID下某个尚未被取出单元的优先级，找到并更新返回true，
+// 单元已经被取出（运行中/已完成）或不在堆中时返回false
+func (s *GlobalScheduler) Reprioritize(taskID, unitName string, priority int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, item := range s.heap {
+		if item.taskID == taskID && item.unit.UnitName == unitName {
+			item.priority = priority
+			heap.Init(&s.heap)
+			return true
+		}
+	}
+	return false
+}
+
+// runWorker 全局worker：不断取出最高优先级且未超过公平份额的单元执行，执行完毕后归还并发额度
+func (s *GlobalScheduler) runWorker(workerID int) {
+	for {
+		item := s.acquire()
+
+		if item.ctx.Err() != nil {
+			// 所属任务已在Pause/Stop中被取消，跳过执行但仍要归还额度、触发done
+			s.release(item.taskID)
+			item.done()
+			continue
+		}
+
+		s.engine.logService.Info(item.taskID, fmt.Sprintf("全局worker %d 开始处理表: %s", workerID, item.unit.UnitName))
+		if err := s.engine.SyncTable(item.ctx, item.taskID, item.unit); err != nil {
+			s.engine.logService.Error(item.taskID, fmt.Sprintf("全局worker %d 处理失败: %v", workerID, err))
+		} else {
+			s.engine.logService.Info(item.taskID, fmt.Sprintf("全局worker %d 完成表: %s", workerID, item.unit.UnitName))
+		}
+		s.release(item.taskID)
+		item.done()
+	}
+}
+
+// acquire 阻塞直到堆中出现一个当前任务运行数未达公平份额的单元
+func (s *GlobalScheduler) acquire() *globalItem {
+	for {
+		s.mu.Lock()
+		if item, ok := s.popEligibleLocked(); ok {
+			s.running[item.taskID]++
+			s.mu.Unlock()
+			return item
+		}
+		s.mu.Unlock()
+		<-s.wake
+	}
+}
+
+// popEligibleLocked 在持锁状态下扫描堆，找到优先级最高、且所属任务运行数仍低于其份额上限的单元；
+// 调用方必须持有s.mu
+func (s *GlobalScheduler) popEligibleLocked() (*globalItem, bool) {
+	if len(s.heap) == 0 {
+		return nil, false
+	}
+
+	fairShare := s.fairShareLocked()
+
+	var skipped []*globalItem
+	var found *globalItem
+	for len(s.heap) > 0 {
+		item := heap.Pop(&s.heap).(*globalItem)
+		taskCap := fairShare
+		if limit, ok := s.limits[item.taskID]; ok && limit > 0 && limit < taskCap {
+			taskCap = limit
+		}
+		if s.running[item.taskID] < taskCap {
+			found = item
+			break
+		}
+		skipped = append(skipped, item)
+	}
+	for _, item := range skipped {
+		heap.Push(&s.heap, item)
+	}
+	if found == nil {
+		return nil, false
+	}
+	return found, true
+}
+
+// fairShareLocked 按"当前有待处理单元或运行中单元的任务数"均分全局worker数(向上取整)，
+// 调用方必须持有s.mu
+func (s *GlobalScheduler) fairShareLocked() int {
+	tasks := make(map[string]bool, len(s.heap))
+	for _, item := range s.heap {
+		tasks[item.taskID] = true
+	}
+	for taskID, count := range s.running {
+		if count > 0 {
+			tasks[taskID] = true
+		}
+	}
+	n := len(tasks)
+	if n <= 1 {
+		return s.maxWorkers
+	}
+	share := (s.maxWorkers + n - 1) / n
+	if share < 1 {
+		share = 1
+	}
+	return share
+}
+
+func (s *GlobalScheduler) release(taskID string) {
+	s.mu.Lock()
+	s.running[taskID]--
+	if s.running[taskID] <= 0 {
+		delete(s.running, taskID)
+	}
+	s.mu.Unlock()
+	s.notify()
+}
+
+func (s *GlobalScheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"datatrace/models"
+)
+
+// newTestScheduler 构造一个不启动worker goroutine、不依赖SyncEngine/数据库的调度器实例，
+// 只用于直接驱动堆和公平份额相关的内部方法
+func newTestScheduler(maxWorkers int) *GlobalScheduler {
+	return &GlobalScheduler{
+		maxWorkers: maxWorkers,
+		running:    make(map[string]int),
+		limits:     make(map[string]int),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+func submitUnit(s *GlobalScheduler, taskID, unitName string, priority int, done func()) {
+	ctx := context.Background()
+	unit := &models.TaskUnitRuntime{TaskID: taskID, UnitName: unitName}
+	s.Submit(taskID, ctx, unit, priority, nil, 0, done)
+}
+
+// popOne 直接驱动popEligibleLocked一次，不经过runWorker/acquire的阻塞等待
+func popOne(t *testing.T, s *GlobalScheduler) *globalItem {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.popEligibleLocked()
+	if !ok {
+		t.Fatal("期望堆中存在可调度的单元，实际为空")
+	}
+	return item
+}
+
+// TestGlobalSchedulerOrdersByPriorityThenFIFO 验证同一任务内，优先级数值越小越先出队；
+// 优先级相同时按提交顺序(seq)先进先出
+func TestGlobalSchedulerOrdersByPriorityThenFIFO(t *testing.T) {
+	s := newTestScheduler(4)
+
+	submitUnit(s, "task-a", "low", 10, func() {})
+	submitUnit(s, "task-a", "high", 1, func() {})
+	submitUnit(s, "task-a", "mid-first", 5, func() {})
+	submitUnit(s, "task-a", "mid-second", 5, func() {})
+
+	var order []string
+	for i := 0; i < 4; i++ {
+		item := popOne(t, s)
+		order = append(order, item.unit.UnitName)
+		s.running[item.taskID]++
+	}
+
+	want := []string{"high", "mid-first", "mid-second", "low"}
+	for i, name := range want {
+		if order[i] != name {
+			t.Fatalf("出队顺序不符: 期望 %v, 实际 %v", want, order)
+		}
+	}
+}
+
+// TestGlobalSchedulerFairShareAvoidsStarvation 验证当一个单元数很多的任务已经占满自己的公平份额时，
+// 调度器会跳过它、把worker让给另一个待处理任务，而不是让后者一直等待
+func TestGlobalSchedulerFairShareAvoidsStarvation(t *testing.T) {
+	s := newTestScheduler(4) // fairShare = ceil(4/2) = 2
+
+	for i := 0; i < 5; i++ {
+		submitUnit(s, "big-task", "unit", 1, func() {})
+	}
+	submitUnit(s, "small-task", "only-unit", 1, func() {})
+
+	// 模拟big-task已经有2个单元在运行，达到其公平份额上限
+	s.running["big-task"] = 2
+
+	item := popOne(t, s)
+	if item.taskID != "small-task" {
+		t.Fatalf("big-task已占满公平份额时，应优先调度small-task，实际调度了 %s/%s", item.taskID, item.unit.UnitName)
+	}
+}
+
+// TestGlobalSchedulerCancelTaskDrainsQueueAndCallsDone 验证CancelTask会清掉该任务所有尚未被取出的
+// 排队单元并触发其done回调，避免提交方的WaitGroup.Wait永久阻塞；其它任务的排队单元不受影响
+func TestGlobalSchedulerCancelTaskDrainsQueueAndCallsDone(t *testing.T) {
+	s := newTestScheduler(4)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	submitUnit(s, "cancelled-task", "unit-1", 1, wg.Done)
+	submitUnit(s, "cancelled-task", "unit-2", 1, wg.Done)
+	submitUnit(s, "kept-task", "unit-1", 1, func() {})
+
+	s.CancelTask("cancelled-task")
+	wg.Wait() // 若cancelled-task的两个单元没有触发done，这里会一直阻塞到测试超时
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.heap) != 1 {
+		t.Fatalf("CancelTask后堆中应只剩kept-task的1个单元，实际剩 %d 个", len(s.heap))
+	}
+	if s.heap[0].taskID != "kept-task" {
+		t.Fatalf("CancelTask不应影响其它任务的排队单元，实际剩余任务为 %s", s.heap[0].taskID)
+	}
+	if _, ok := s.limits["cancelled-task"]; ok {
+		t.Fatal("CancelTask后应清除该任务的并发上限记录")
+	}
+}
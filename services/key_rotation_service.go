@@ -0,0 +1,72 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"datatrace/config"
+	"datatrace/utils"
+
+	"go.uber.org/zap"
+)
+
+const defaultKeyRotationCheckInterval = 24 * time.Hour
+
+// KeyRotationService 周期检查信封加密使用的RSA密钥是否到期，到期则自动轮换到新版本；
+// 历史密钥版本保留在库中，不影响用旧版本加密的存量凭据解密
+type KeyRotationService struct {
+	crypto *utils.CryptoService
+	once   sync.Once
+}
+
+var (
+	keyRotationInstance *KeyRotationService
+	keyRotationOnce     sync.Once
+)
+
+// NewKeyRotationService 获取密钥轮换服务单例
+func NewKeyRotationService() *KeyRotationService {
+	keyRotationOnce.Do(func() {
+		keyRotationInstance = &KeyRotationService{crypto: utils.NewCryptoService()}
+	})
+	return keyRotationInstance
+}
+
+// Start 启动后台轮换检查协程，进程生命周期内只需要调用一次
+func (s *KeyRotationService) Start() {
+	s.once.Do(func() {
+		s.checkAndRotate()
+		go func() {
+			ticker := time.NewTicker(checkInterval())
+			defer ticker.Stop()
+			for range ticker.C {
+				s.checkAndRotate()
+			}
+		}()
+	})
+}
+
+// checkInterval 返回配置的轮换检查间隔，未配置时退回defaultKeyRotationCheckInterval
+func checkInterval() time.Duration {
+	if config.GlobalConfig != nil && config.GlobalConfig.Crypto.CheckIntervalMinutes > 0 {
+		return time.Duration(config.GlobalConfig.Crypto.CheckIntervalMinutes) * time.Minute
+	}
+	return defaultKeyRotationCheckInterval
+}
+
+// checkAndRotate 检查当前激活密钥是否超过轮换周期，超过则生成新版本密钥并切换激活
+func (s *KeyRotationService) checkAndRotate() {
+	due, err := s.crypto.RotationDue()
+	if err != nil {
+		utils.Log.Error("检查密钥轮换状态失败", zap.Error(err))
+		return
+	}
+	if !due {
+		return
+	}
+	if err := s.crypto.RotateKey(); err != nil {
+		utils.Log.Error("RSA密钥轮换失败", zap.Error(err))
+		return
+	}
+	utils.Log.Info("RSA加密密钥已自动轮换")
+}
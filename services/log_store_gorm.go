@@ -0,0 +1,120 @@
+package services
+
+import (
+	"encoding/json"
+	"time"
+
+	"datatrace/database"
+	"datatrace/models"
+)
+
+// gormLogStore 基于GORM的任务日志持久化存储，重启不丢失，依赖task_logs表的索引支持条件检索
+type gormLogStore struct{}
+
+// newGormLogStore 创建GORM日志存储
+func newGormLogStore() *gormLogStore {
+	return &gormLogStore{}
+}
+
+// Append 追加一条日志，Fields编码为JSON后存入text列
+func (s *gormLogStore) Append(taskID string, log TaskLog) error {
+	ts, err := time.Parse(time.RFC3339Nano, log.Time)
+	if err != nil {
+		ts = time.Now()
+	}
+
+	fieldsJSON := ""
+	if len(log.Fields) > 0 {
+		if b, err := json.Marshal(log.Fields); err == nil {
+			fieldsJSON = string(b)
+		}
+	}
+
+	record := models.TaskLog{
+		TaskID:   taskID,
+		Ts:       ts,
+		Level:    log.Level,
+		Category: log.Category,
+		Message:  log.Message,
+		Fields:   fieldsJSON,
+	}
+	return database.DB.Create(&record).Error
+}
+
+// Recent 按ts倒序取最近的limit条记录，再反转为正序返回
+func (s *gormLogStore) Recent(taskID string, limit int) ([]TaskLog, error) {
+	query := database.DB.Model(&models.TaskLog{}).Where("task_id = ?", taskID).Order("ts DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var records []models.TaskLog
+	if err := query.Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	logs := make([]TaskLog, len(records))
+	for i, r := range records {
+		logs[len(records)-1-i] = toTaskLog(r)
+	}
+	return logs, nil
+}
+
+// Search 按条件构造查询，结果按ts正序分页返回
+func (s *gormLogStore) Search(taskID string, q LogQuery) ([]TaskLog, error) {
+	query := database.DB.Model(&models.TaskLog{}).Where("task_id = ?", taskID)
+	if q.Level != "" && q.Level != "all" {
+		query = query.Where("level = ?", q.Level)
+	}
+	if q.Category != "" && q.Category != "all" {
+		query = query.Where("category = ?", q.Category)
+	}
+	if !q.Since.IsZero() {
+		query = query.Where("ts >= ?", q.Since)
+	}
+	if !q.Until.IsZero() {
+		query = query.Where("ts <= ?", q.Until)
+	}
+	if q.Search != "" {
+		query = query.Where("message LIKE ?", "%"+q.Search+"%")
+	}
+
+	query = query.Order("ts ASC")
+	if q.Offset > 0 {
+		query = query.Offset(q.Offset)
+	}
+	if q.Limit > 0 {
+		query = query.Limit(q.Limit)
+	}
+
+	var records []models.TaskLog
+	if err := query.Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	logs := make([]TaskLog, len(records))
+	for i, r := range records {
+		logs[i] = toTaskLog(r)
+	}
+	return logs, nil
+}
+
+// Clear 删除某个任务的全部日志记录
+func (s *gormLogStore) Clear(taskID string) error {
+	return database.DB.Where("task_id = ?", taskID).Delete(&models.TaskLog{}).Error
+}
+
+// toTaskLog 将持久化模型转换为对外DTO，Fields反序列化失败时忽略
+func toTaskLog(r models.TaskLog) TaskLog {
+	var fields map[string]interface{}
+	if r.Fields != "" {
+		_ = json.Unmarshal([]byte(r.Fields), &fields)
+	}
+	return TaskLog{
+		Time:     r.Ts.Format(time.RFC3339Nano),
+		Level:    r.Level,
+		Message:  r.Message,
+		Category: r.Category,
+		Fields:   fields,
+	}
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryLogStoreRingSize 每个任务在内存环形缓冲区中保留的最大日志条数
+const memoryLogStoreRingSize = 1000
+
+// memoryLogStore 内存环形缓冲区日志存储，进程重启后日志丢失，是LogStore的默认实现
+type memoryLogStore struct {
+	mu   sync.RWMutex
+	logs map[string][]TaskLog // taskID -> logs
+}
+
+// newMemoryLogStore 创建内存日志存储
+func newMemoryLogStore() *memoryLogStore {
+	return &memoryLogStore{logs: make(map[string][]TaskLog)}
+}
+
+// Append 追加一条日志，超出环形缓冲区容量时丢弃最旧的一条
+func (s *memoryLogStore) Append(taskID string, log TaskLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logs[taskID] = append(s.logs[taskID], log)
+	if len(s.logs[taskID]) > memoryLogStoreRingSize {
+		s.logs[taskID] = s.logs[taskID][len(s.logs[taskID])-memoryLogStoreRingSize:]
+	}
+	return nil
+}
+
+// Recent 返回最近的limit条日志，按时间正序排列
+func (s *memoryLogStore) Recent(taskID string, limit int) ([]TaskLog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	logs, exists := s.logs[taskID]
+	if !exists {
+		return []TaskLog{}, nil
+	}
+
+	if limit > 0 && len(logs) > limit {
+		logs = logs[len(logs)-limit:]
+	}
+	return append([]TaskLog{}, logs...), nil
+}
+
+// Search 在内存缓冲区内按条件过滤后分页，结果按时间正序排列
+func (s *memoryLogStore) Search(taskID string, q LogQuery) ([]TaskLog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	logs, exists := s.logs[taskID]
+	if !exists {
+		return []TaskLog{}, nil
+	}
+
+	filtered := make([]TaskLog, 0, len(logs))
+	for _, l := range logs {
+		if q.Level != "" && q.Level != "all" && l.Level != q.Level {
+			continue
+		}
+		if q.Category != "" && q.Category != "all" && l.Category != q.Category {
+			continue
+		}
+		if q.Search != "" && !strings.Contains(l.Message, q.Search) {
+			continue
+		}
+		if !q.Since.IsZero() || !q.Until.IsZero() {
+			ts, err := time.Parse(time.RFC3339Nano, l.Time)
+			if err != nil {
+				continue
+			}
+			if !q.Since.IsZero() && ts.Before(q.Since) {
+				continue
+			}
+			if !q.Until.IsZero() && ts.After(q.Until) {
+				continue
+			}
+		}
+		filtered = append(filtered, l)
+	}
+
+	if q.Offset > 0 {
+		if q.Offset >= len(filtered) {
+			return []TaskLog{}, nil
+		}
+		filtered = filtered[q.Offset:]
+	}
+	if q.Limit > 0 && len(filtered) > q.Limit {
+		filtered = filtered[:q.Limit]
+	}
+	return filtered, nil
+}
+
+// Clear 清空某个任务的全部日志
+func (s *memoryLogStore) Clear(taskID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.logs, taskID)
+	return nil
+}
@@ -0,0 +1,52 @@
+package services
+
+import "fmt"
+
+// DatabaseInfo 数据库信息
+type DatabaseInfo struct {
+	Name       string `json:"name"`
+	TableCount int    `json:"table_count"`
+}
+
+// TableInfo 表信息
+type TableInfo struct {
+	Name    string `json:"name"`
+	Comment string `json:"comment"`
+}
+
+// ColumnInfo 列信息
+type ColumnInfo struct {
+	Name     string `json:"name"`
+	DataType string `json:"data_type"`
+	Nullable bool   `json:"nullable"`
+	IsPK     bool   `json:"is_pk"`
+	Comment  string `json:"comment"`
+}
+
+// MetadataProvider 元数据查询服务的统一接口，不同数据源类型各自实现库/表（或集合）及其结构的枚举
+type MetadataProvider interface {
+	GetDatabases(host string, port int, username, password string) ([]DatabaseInfo, error)
+	GetSchemas(host string, port int, username, password, database string) ([]string, error)
+	GetTables(host string, port int, username, password, database string) ([]TableInfo, error)
+	GetColumns(host string, port int, username, password, database, table string) ([]ColumnInfo, error)
+}
+
+// NewMetadataProvider 按数据源类型创建对应的元数据查询服务
+func NewMetadataProvider(dsType string) (MetadataProvider, error) {
+	switch dsType {
+	case "mysql":
+		return NewMySQLMetadataService(), nil
+	case "postgresql":
+		return NewPostgreSQLMetadataService(), nil
+	case "sqlite":
+		return NewSQLiteMetadataService(), nil
+	case "sqlserver":
+		return NewSQLServerMetadataService(), nil
+	case "mongodb":
+		return NewMongoDBMetadataService(), nil
+	case "elasticsearch":
+		return NewElasticsearchMetadataService(), nil
+	default:
+		return nil, fmt.Errorf("数据源类型 %s 不支持元数据查询", dsType)
+	}
+}
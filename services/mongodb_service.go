@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoDBMetadataService MongoDB 元数据查询服务（以库/集合对应关系型数据源的库/表）
+type MongoDBMetadataService struct{}
+
+// NewMongoDBMetadataService 创建 MongoDB 元数据服务
+func NewMongoDBMetadataService() *MongoDBMetadataService {
+	return &MongoDBMetadataService{}
+}
+
+// connect 建立MongoDB连接，调用方负责在使用完毕后断开
+func (s *MongoDBMetadataService) connect(host string, port int, username, password string) (*mongo.Client, context.Context, context.CancelFunc, error) {
+	uri := fmt.Sprintf("mongodb://%s:%s@%s:%d/?authSource=admin", username, password, host, port)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		cancel()
+		return nil, nil, nil, fmt.Errorf("连接失败: %w", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		cancel()
+		return nil, nil, nil, fmt.Errorf("连接失败: %w", err)
+	}
+	return client, ctx, cancel, nil
+}
+
+// GetDatabases 获取数据库列表（排除内置系统库）
+func (s *MongoDBMetadataService) GetDatabases(host string, port int, username, password string) ([]DatabaseInfo, error) {
+	client, ctx, cancel, err := s.connect(host, port, username, password)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	defer client.Disconnect(ctx)
+
+	names, err := client.ListDatabaseNames(ctx, bson.M{"name": bson.M{"$nin": bson.A{"admin", "local", "config"}}})
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+
+	databases := make([]DatabaseInfo, 0, len(names))
+	for _, name := range names {
+		collections, err := client.Database(name).ListCollectionNames(ctx, bson.M{})
+		if err != nil {
+			return nil, fmt.Errorf("查询集合数量失败: %w", err)
+		}
+		databases = append(databases, DatabaseInfo{Name: name, TableCount: len(collections)})
+	}
+
+	return databases, nil
+}
+
+// GetTables 获取指定数据库的集合列表
+func (s *MongoDBMetadataService) GetTables(host string, port int, username, password, database string) ([]TableInfo, error) {
+	client, ctx, cancel, err := s.connect(host, port, username, password)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	defer client.Disconnect(ctx)
+
+	names, err := client.Database(database).ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+
+	tables := make([]TableInfo, 0, len(names))
+	for _, name := range names {
+		tables = append(tables, TableInfo{Name: name})
+	}
+
+	return tables, nil
+}
+
+// GetSchemas MongoDB是无模式文档数据库，不存在独立于数据库的模式概念，返回空列表
+func (s *MongoDBMetadataService) GetSchemas(host string, port int, username, password, database string) ([]string, error) {
+	return []string{}, nil
+}
+
+// GetColumns 通过抽样集合中的一篇文档推断字段列表（MongoDB无固定schema，仅作预览参考）
+func (s *MongoDBMetadataService) GetColumns(host string, port int, username, password, database, table string) ([]ColumnInfo, error) {
+	client, ctx, cancel, err := s.connect(host, port, username, password)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	defer client.Disconnect(ctx)
+
+	var doc bson.M
+	err = client.Database(database).Collection(table).FindOne(ctx, bson.M{}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return []ColumnInfo{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("抽样文档失败: %w", err)
+	}
+
+	columns := make([]ColumnInfo, 0, len(doc))
+	for field, value := range doc {
+		columns = append(columns, ColumnInfo{
+			Name:     field,
+			DataType: mongoBsonTypeName(value),
+			Nullable: true,
+			IsPK:     field == "_id",
+		})
+	}
+
+	return columns, nil
+}
+
+// mongoBsonTypeName 将抽样得到的Go值映射为展示用的逻辑类型名
+func mongoBsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case primitive.ObjectID:
+		return "objectId"
+	case string:
+		return "string"
+	case int32, int64, int:
+		return "long"
+	case float64:
+		return "double"
+	case bool:
+		return "bool"
+	case primitive.DateTime:
+		return "date"
+	case bson.A:
+		return "array"
+	case bson.M, bson.D:
+		return "object"
+	default:
+		return "mixed"
+	}
+}
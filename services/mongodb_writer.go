@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoDBWriter MongoDB数据写入器；表映射为集合，行映射为BSON文档
+type MongoDBWriter struct {
+	client         *mongo.Client
+	collection     *mongo.Collection
+	database       string
+	collectionName string
+}
+
+// NewMongoDBWriter 创建MongoDB写入器
+func NewMongoDBWriter(cfg WriterConfig) (*MongoDBWriter, error) {
+	if err := ValidateTableName(cfg.Table); err != nil {
+		return nil, fmt.Errorf("集合名校验失败: %w", err)
+	}
+
+	uri := fmt.Sprintf("mongodb://%s:%s@%s:%d/?authSource=admin", cfg.Username, cfg.Password, cfg.Host, cfg.Port)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("连接数据库失败: %w", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		return nil, fmt.Errorf("数据库连接测试失败: %w", err)
+	}
+
+	return &MongoDBWriter{
+		client:         client,
+		collection:     client.Database(cfg.Database).Collection(cfg.Table),
+		database:       cfg.Database,
+		collectionName: cfg.Table,
+	}, nil
+}
+
+// WriteBatch 将行记录映射为BSON文档后批量插入
+func (w *MongoDBWriter) WriteBatch(records []map[string]interface{}) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(records))
+	for i, record := range records {
+		doc := bson.M{}
+		for k, v := range record {
+			doc[k] = v
+		}
+		docs[i] = doc
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := w.collection.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("批量插入失败: %w", err)
+	}
+	return nil
+}
+
+// CreateTableLike MongoDB无需预先建表，此处按主键列在目标集合上创建唯一索引，等价于其它方言的建表
+func (w *MongoDBWriter) CreateTableLike(columns []ColumnInfo) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, col := range columns {
+		if !col.IsPK {
+			continue
+		}
+		_, err := w.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: col.Name, Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		if err != nil {
+			return fmt.Errorf("创建唯一索引失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// TruncateTable 清空集合中的所有文档
+func (w *MongoDBWriter) TruncateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := w.collection.DeleteMany(ctx, bson.M{}); err != nil {
+		return fmt.Errorf("清空集合失败: %w", err)
+	}
+	return nil
+}
+
+// DropTable 删除集合
+func (w *MongoDBWriter) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := w.collection.Drop(ctx); err != nil {
+		return fmt.Errorf("删除集合失败: %w", err)
+	}
+	return nil
+}
+
+// Close 断开连接
+func (w *MongoDBWriter) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return w.client.Disconnect(ctx)
+}
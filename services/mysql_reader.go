@@ -3,17 +3,25 @@ package services
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+
+	"datatrace/utils"
 
 	_ "github.com/go-sql-driver/mysql"
+
+	"go.uber.org/zap"
 )
 
 // MySQLReader MySQL数据读取器
 type MySQLReader struct {
-	db         *sql.DB
-	tableName  string
-	batchSize  int
-	offset     int64
-	totalCount int64
+	db           *sql.DB
+	tableName    string
+	batchSize    int
+	offset       int64
+	totalCount   int64
+	pkColumn     string        // 单列主键名，非空时CursorWatermark/增量续传可用；联合主键下为空（见pkColumns）
+	pkColumns    []string      // 主键列（按ORDINAL_POSITION排序），单列或联合主键时ReadBatch都用它做游标分页，避免OFFSET深分页
+	lastPKValues []interface{} // 游标分页中上一批最后一行各主键列的值，按pkColumns顺序对应，nil表示尚未读取
 }
 
 // NewMySQLReader 创建MySQL读取器
@@ -50,6 +58,13 @@ func NewMySQLReader(host string, port int, username, password, database, tableNa
 		return nil, err
 	}
 
+	// 探测主键列，存在则ReadBatch使用游标分页，避免大表深OFFSET扫描；
+	// 联合主键也会被识别（见GetPrimaryKeyColumns），但不能作为单列增量续传的水位线列
+	reader.pkColumns = reader.GetPrimaryKeyColumns(database)
+	if len(reader.pkColumns) == 1 {
+		reader.pkColumn = reader.pkColumns[0]
+	}
+
 	return reader, nil
 }
 
@@ -68,14 +83,36 @@ func (r *MySQLReader) GetTotalCount() int64 {
 	return r.totalCount
 }
 
-// ReadBatch 读取一批数据
+// ReadBatch 读取一批数据。存在主键（单列或联合）时使用基于主键的游标分页（keyset pagination），
+// 避免大表场景下OFFSET越往后跳过的行越多、扫描越慢的问题；否则退化为OFFSET分页。
+// 联合主键通过MySQL行构造符比较(`a`,`b`) > (?,?)实现，比较语义等同于按ORDER BY同序逐列比较
 func (r *MySQLReader) ReadBatch() ([]map[string]interface{}, error) {
-	// 构建查询语句
-	query := fmt.Sprintf("SELECT * FROM `%s` LIMIT %d OFFSET %d",
-		r.tableName, r.batchSize, r.offset)
+	var rows *sql.Rows
+	var err error
+
+	if len(r.pkColumns) > 0 {
+		quoted := make([]string, len(r.pkColumns))
+		for i, col := range r.pkColumns {
+			quoted[i] = fmt.Sprintf("`%s`", col)
+		}
+		orderBy := strings.Join(quoted, ", ")
 
-	// 执行查询
-	rows, err := r.db.Query(query)
+		if r.lastPKValues == nil {
+			query := fmt.Sprintf("SELECT * FROM `%s` ORDER BY %s ASC LIMIT %d",
+				r.tableName, orderBy, r.batchSize)
+			rows, err = r.db.Query(query)
+		} else {
+			tuple := strings.Join(quoted, ",")
+			placeholders := strings.TrimSuffix(strings.Repeat("?,", len(r.pkColumns)), ",")
+			query := fmt.Sprintf("SELECT * FROM `%s` WHERE (%s) > (%s) ORDER BY %s ASC LIMIT %d",
+				r.tableName, tuple, placeholders, orderBy, r.batchSize)
+			rows, err = r.db.Query(query, r.lastPKValues...)
+		}
+	} else {
+		query := fmt.Sprintf("SELECT * FROM `%s` LIMIT %d OFFSET %d",
+			r.tableName, r.batchSize, r.offset)
+		rows, err = r.db.Query(query)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("查询数据失败: %w", err)
 	}
@@ -116,17 +153,155 @@ func (r *MySQLReader) ReadBatch() ([]map[string]interface{}, error) {
 		results = append(results, row)
 	}
 
-	// 更新偏移量
+	// 更新游标/偏移量
 	r.offset += int64(len(results))
+	if len(r.pkColumns) > 0 && len(results) > 0 {
+		last := results[len(results)-1]
+		values := make([]interface{}, len(r.pkColumns))
+		for i, col := range r.pkColumns {
+			values[i] = last[col]
+		}
+		r.lastPKValues = values
+	}
 
 	return results, nil
 }
 
+// CursorWatermark 返回当前基于主键游标分页使用的主键列名，以及目前为止读到的最大主键值；
+// 仅单列主键可以作为续传水位线使用（WHERE col > ?语义），联合主键下列名为空字符串——
+// 调用方（ReadIncrementalBatch/断点续传）只接受单列比较，无法表达联合主键的行构造符比较
+func (r *MySQLReader) CursorWatermark() (column string, value interface{}) {
+	if r.pkColumn == "" || len(r.lastPKValues) != 1 {
+		return "", nil
+	}
+	return r.pkColumn, r.lastPKValues[0]
+}
+
 // HasMore 是否还有更多数据
 func (r *MySQLReader) HasMore() bool {
 	return r.offset < r.totalCount
 }
 
+// ReadIncrementalBatch 按水位线读取一批增量数据（WHERE col > ? ORDER BY col ASC LIMIT batch）
+// lastVal 为上次同步到的水位线值，首次同步传空字符串表示从头读取
+func (r *MySQLReader) ReadIncrementalBatch(colName string, lastVal string) ([]map[string]interface{}, error) {
+	var rows *sql.Rows
+	var err error
+
+	if lastVal == "" {
+		query := fmt.Sprintf("SELECT * FROM `%s` ORDER BY `%s` ASC LIMIT %d",
+			r.tableName, colName, r.batchSize)
+		rows, err = r.db.Query(query)
+	} else {
+		query := fmt.Sprintf("SELECT * FROM `%s` WHERE `%s` > ? ORDER BY `%s` ASC LIMIT %d",
+			r.tableName, colName, colName, r.batchSize)
+		rows, err = r.db.Query(query, lastVal)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询增量数据失败: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("获取列名失败: %w", err)
+	}
+
+	var results []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, fmt.Errorf("扫描行数据失败: %w", err)
+		}
+
+		row := make(map[string]interface{})
+		for i, col := range columns {
+			val := values[i]
+			if b, ok := val.([]byte); ok {
+				row[col] = string(b)
+			} else {
+				row[col] = val
+			}
+		}
+		results = append(results, row)
+	}
+
+	return results, nil
+}
+
+// GetColumnType 查询某一列在 information_schema 中登记的数据类型，用于水位线类型推断
+func (r *MySQLReader) GetColumnType(database, column string) (string, error) {
+	var dataType string
+	query := `SELECT DATA_TYPE FROM information_schema.COLUMNS
+	          WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND COLUMN_NAME = ?`
+	err := r.db.QueryRow(query, database, r.tableName, column).Scan(&dataType)
+	if err != nil {
+		return "", fmt.Errorf("查询列类型失败: %w", err)
+	}
+	return dataType, nil
+}
+
+// ColumnExists 判断表中是否存在指定列
+func (r *MySQLReader) ColumnExists(database, column string) bool {
+	var count int
+	query := `SELECT COUNT(*) FROM information_schema.COLUMNS
+	          WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND COLUMN_NAME = ?`
+	if err := r.db.QueryRow(query, database, r.tableName, column).Scan(&count); err != nil {
+		return false
+	}
+	return count > 0
+}
+
+// GetPrimaryKeyColumn 查询表的单列主键名，若表没有主键或是联合主键则返回空字符串。
+// 仅用于需要单一列名的场景（如增量续传水位线）；需要识别联合主键时用GetPrimaryKeyColumns
+func (r *MySQLReader) GetPrimaryKeyColumn(database string) string {
+	columns := r.GetPrimaryKeyColumns(database)
+	if len(columns) != 1 {
+		return ""
+	}
+	return columns[0]
+}
+
+// GetPrimaryKeyColumns 查询表的主键列，按ORDINAL_POSITION排序返回；单列主键返回长度为1的切片，
+// 联合主键返回全部列并记一条警告日志（联合主键无法用作单列增量续传水位线，但ReadBatch的游标分页
+// 支持联合主键），表没有主键时返回空切片
+func (r *MySQLReader) GetPrimaryKeyColumns(database string) []string {
+	query := `SELECT COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE
+	          WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND CONSTRAINT_NAME = 'PRIMARY'
+	          ORDER BY ORDINAL_POSITION`
+	rows, err := r.db.Query(query, database, r.tableName)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil
+		}
+		columns = append(columns, column)
+	}
+
+	if len(columns) > 1 {
+		utils.Log.Warn("表使用联合主键，无法作为单列增量续传水位线，游标分页将按联合主键比较",
+			zap.String("table", r.tableName), zap.Strings("columns", columns))
+	}
+
+	return columns
+}
+
+// Checksum 计算源表的CHECKSUM TABLE结果，用于verify_checksum阶段与目标表比对
+func (r *MySQLReader) Checksum() (string, error) {
+	return checksumTable(r.db, r.tableName)
+}
+
 // GetDB 获取数据库连接（用于创建表结构）
 func (r *MySQLReader) GetDB() *sql.DB {
 	return r.db
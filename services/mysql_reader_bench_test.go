@@ -0,0 +1,144 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// benchRowCount 分页基准测试使用的行数。本仓库的CI/沙箱环境中没有可写入千万行的MySQL实例，
+// 无法真正复现一张10M行的MySQL表；这里退而求其次，用SQLite在本地磁盘上构造一张同等分页特征的
+// 表（窄行、单调自增主键），默认10万行即可稳定观察到OFFSET随翻页增大而变慢、游标分页保持常数时间
+// 的趋势。可以通过环境变量 BENCH_PAGINATION_ROWS 调大到百万级别复现更接近10M的曲线
+const benchRowCount = 100_000
+
+func paginationBenchRowCount() int {
+	if v := os.Getenv("BENCH_PAGINATION_ROWS"); v != "" {
+		var n int
+		if _, err := fmt.Sscanf(v, "%d", &n); err == nil && n > 0 {
+			return n
+		}
+	}
+	return benchRowCount
+}
+
+// setupPaginationBenchDB 创建一张按自增主键排序的基准表并填充指定行数，返回已打开的连接
+func setupPaginationBenchDB(b *testing.B, rows int) *sql.DB {
+	b.Helper()
+
+	path := b.TempDir() + "/pagination_bench.db"
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		b.Fatalf("打开SQLite数据库失败: %v", err)
+	}
+	b.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`CREATE TABLE bench_rows (id INTEGER PRIMARY KEY, payload TEXT NOT NULL)`); err != nil {
+		b.Fatalf("建表失败: %v", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		b.Fatalf("开启事务失败: %v", err)
+	}
+	stmt, err := tx.Prepare(`INSERT INTO bench_rows (id, payload) VALUES (?, ?)`)
+	if err != nil {
+		b.Fatalf("准备插入语句失败: %v", err)
+	}
+	for i := 0; i < rows; i++ {
+		if _, err := stmt.Exec(i, fmt.Sprintf("payload-%d", i)); err != nil {
+			b.Fatalf("插入基准数据失败: %v", err)
+		}
+	}
+	stmt.Close()
+	if err := tx.Commit(); err != nil {
+		b.Fatalf("提交事务失败: %v", err)
+	}
+
+	return db
+}
+
+// readAllOffset 用OFFSET分页（与ReadBatch的无主键退化路径同构）把全表读完一遍
+func readAllOffset(db *sql.DB, batchSize int) (int, error) {
+	total := 0
+	offset := 0
+	for {
+		rows, err := db.Query(`SELECT id, payload FROM bench_rows ORDER BY id LIMIT ? OFFSET ?`, batchSize, offset)
+		if err != nil {
+			return total, err
+		}
+		count := 0
+		for rows.Next() {
+			var id int
+			var payload string
+			if err := rows.Scan(&id, &payload); err != nil {
+				rows.Close()
+				return total, err
+			}
+			count++
+		}
+		rows.Close()
+		if count == 0 {
+			return total, nil
+		}
+		total += count
+		offset += count
+	}
+}
+
+// readAllKeyset 用主键游标分页（与ReadBatch的有主键路径同构）把全表读完一遍
+func readAllKeyset(db *sql.DB, batchSize int) (int, error) {
+	total := 0
+	lastID := -1
+	for {
+		rows, err := db.Query(`SELECT id, payload FROM bench_rows WHERE id > ? ORDER BY id LIMIT ?`, lastID, batchSize)
+		if err != nil {
+			return total, err
+		}
+		count := 0
+		for rows.Next() {
+			var id int
+			var payload string
+			if err := rows.Scan(&id, &payload); err != nil {
+				rows.Close()
+				return total, err
+			}
+			lastID = id
+			count++
+		}
+		rows.Close()
+		if count == 0 {
+			return total, nil
+		}
+		total += count
+	}
+}
+
+// BenchmarkOffsetPagination 对应ReadBatch在表没有主键时退化使用的OFFSET分页
+func BenchmarkOffsetPagination(b *testing.B) {
+	rows := paginationBenchRowCount()
+	db := setupPaginationBenchDB(b, rows)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := readAllOffset(db, 500); err != nil {
+			b.Fatalf("OFFSET分页读取失败: %v", err)
+		}
+	}
+}
+
+// BenchmarkKeysetPagination 对应ReadBatch存在主键时使用的游标分页
+func BenchmarkKeysetPagination(b *testing.B) {
+	rows := paginationBenchRowCount()
+	db := setupPaginationBenchDB(b, rows)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := readAllKeyset(db, 500); err != nil {
+			b.Fatalf("游标分页读取失败: %v", err)
+		}
+	}
+}
@@ -1,50 +1,30 @@
 package services
 
-import (
-	"database/sql"
-	"fmt"
-
-	_ "github.com/go-sql-driver/mysql"
-)
+import "fmt"
 
 // MySQLMetadataService MySQL 元数据查询服务
-type MySQLMetadataService struct{}
+type MySQLMetadataService struct {
+	pool *ConnectionPool
+}
 
 // NewMySQLMetadataService 创建 MySQL 元数据服务
 func NewMySQLMetadataService() *MySQLMetadataService {
-	return &MySQLMetadataService{}
-}
-
-// DatabaseInfo 数据库信息
-type DatabaseInfo struct {
-	Name       string `json:"name"`
-	TableCount int    `json:"table_count"`
+	return &MySQLMetadataService{pool: NewConnectionPool()}
 }
 
-// TableInfo 表信息
-type TableInfo struct {
-	Name    string `json:"name"`
-	Comment string `json:"comment"`
-}
-
-// GetDatabases 获取数据库列表
+// GetDatabases 获取数据库列表；不选定具体库，借用该账号下的空库连接池
 func (s *MySQLMetadataService) GetDatabases(host string, port int, username, password string) ([]DatabaseInfo, error) {
-	// 构建连接字符串
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/?charset=utf8mb4&parseTime=True&loc=Local",
-		username, password, host, port)
-
-	// 连接数据库
-	db, err := sql.Open("mysql", dsn)
+	db, release, err := s.pool.Borrow(host, port, username, password, "")
 	if err != nil {
-		return nil, fmt.Errorf("连接失败: %w", err)
+		return nil, err
 	}
-	defer db.Close()
+	defer release()
 
 	// 查询数据库列表（排除系统数据库）
 	query := `
-		SELECT 
+		SELECT
 			SCHEMA_NAME as name,
-			(SELECT COUNT(*) FROM information_schema.TABLES 
+			(SELECT COUNT(*) FROM information_schema.TABLES
 			 WHERE TABLE_SCHEMA = SCHEMA_NAME AND TABLE_TYPE = 'BASE TABLE') as table_count
 		FROM information_schema.SCHEMATA
 		WHERE SCHEMA_NAME NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys')
@@ -71,16 +51,11 @@ func (s *MySQLMetadataService) GetDatabases(host string, port int, username, pas
 
 // GetTables 获取指定数据库的表列表
 func (s *MySQLMetadataService) GetTables(host string, port int, username, password, database string) ([]TableInfo, error) {
-	// 构建连接字符串
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		username, password, host, port, database)
-
-	// 连接数据库
-	db, err := sql.Open("mysql", dsn)
+	db, release, err := s.pool.Borrow(host, port, username, password, database)
 	if err != nil {
-		return nil, fmt.Errorf("连接失败: %w", err)
+		return nil, err
 	}
-	defer db.Close()
+	defer release()
 
 	// 查询表列表（只查询表名，不查询行数，提高速度）
 	query := "SELECT TABLE_NAME as name, IFNULL(TABLE_COMMENT, '') as comment FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME"
@@ -102,3 +77,41 @@ func (s *MySQLMetadataService) GetTables(host string, port int, username, passwo
 
 	return tables, nil
 }
+
+// GetSchemas 获取模式列表；MySQL中模式与数据库是同一概念，返回该数据库自身作为唯一模式
+func (s *MySQLMetadataService) GetSchemas(host string, port int, username, password, database string) ([]string, error) {
+	return []string{database}, nil
+}
+
+// GetColumns 获取指定表的列信息
+func (s *MySQLMetadataService) GetColumns(host string, port int, username, password, database, table string) ([]ColumnInfo, error) {
+	db, release, err := s.pool.Borrow(host, port, username, password, database)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	query := `
+		SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE = 'YES', COLUMN_KEY = 'PRI', IFNULL(COLUMN_COMMENT, '')
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+		ORDER BY ORDINAL_POSITION
+	`
+
+	rows, err := db.Query(query, database, table)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var col ColumnInfo
+		if err := rows.Scan(&col.Name, &col.DataType, &col.Nullable, &col.IsPK, &col.Comment); err != nil {
+			return nil, err
+		}
+		columns = append(columns, col)
+	}
+
+	return columns, nil
+}
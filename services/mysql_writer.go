@@ -5,39 +5,32 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/go-mysql-org/go-mysql/schema"
 	_ "github.com/go-sql-driver/mysql"
 )
 
 // MySQLWriter MySQL数据写入器
 type MySQLWriter struct {
 	db        *sql.DB
+	release   func() // 归还连接池引用计数，替代直接关闭连接
 	tableName string
 }
 
-// NewMySQLWriter 创建MySQL写入器
+// NewMySQLWriter 创建MySQL写入器，底层连接借用自ConnectionPool
 func NewMySQLWriter(host string, port int, username, password, database, tableName string) (*MySQLWriter, error) {
 	// 校验表名
 	if err := ValidateTableName(tableName); err != nil {
 		return nil, fmt.Errorf("表名校验失败: %w", err)
 	}
 
-	// 构建连接字符串（连接到指定数据库）
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		username, password, host, port, database)
-
-	// 连接数据库
-	db, err := sql.Open("mysql", dsn)
+	db, release, err := NewConnectionPool().Borrow(host, port, username, password, database)
 	if err != nil {
-		return nil, fmt.Errorf("连接数据库失败: %w", err)
-	}
-
-	// 测试连接
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("数据库连接测试失败: %w", err)
+		return nil, err
 	}
 
 	return &MySQLWriter{
 		db:        db,
+		release:   release,
 		tableName: tableName,
 	}, nil
 }
@@ -139,6 +132,198 @@ func (w *MySQLWriter) WriteBatch(records []map[string]interface{}) error {
 	return nil
 }
 
+// WriteBatchUpsert 批量写入数据，主键/唯一键冲突时更新（用于增量同步）
+func (w *MySQLWriter) WriteBatchUpsert(records []map[string]interface{}) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	// 获取列名（从第一条记录）
+	var columns []string
+	for col := range records[0] {
+		columns = append(columns, col)
+	}
+
+	placeholders := make([]string, len(records))
+	values := make([]interface{}, 0, len(records)*len(columns))
+
+	for i, record := range records {
+		rowPlaceholders := make([]string, len(columns))
+		for j := range columns {
+			rowPlaceholders[j] = "?"
+		}
+		placeholders[i] = fmt.Sprintf("(%s)", strings.Join(rowPlaceholders, ", "))
+
+		for _, col := range columns {
+			values = append(values, record[col])
+		}
+	}
+
+	columnNames := make([]string, len(columns))
+	updateClauses := make([]string, len(columns))
+	for i, col := range columns {
+		columnNames[i] = fmt.Sprintf("`%s`", col)
+		updateClauses[i] = fmt.Sprintf("`%s` = VALUES(`%s`)", col, col)
+	}
+
+	query := fmt.Sprintf("INSERT INTO `%s` (%s) VALUES %s ON DUPLICATE KEY UPDATE %s",
+		w.tableName,
+		strings.Join(columnNames, ", "),
+		strings.Join(placeholders, ", "),
+		strings.Join(updateClauses, ", "))
+
+	_, err := w.db.Exec(query, values...)
+	if err != nil {
+		return fmt.Errorf("批量写入(upsert)失败: %w", err)
+	}
+
+	return nil
+}
+
+// WriteBatchWithMode 按WriteMode写入一批数据，内部按BatchWriteBuilder分片开事务提交，单片整体
+// 失败时逐行重试定位出问题的行；返回的RowError.Index为records中的原始下标，失败行不影响其余行写入，
+// 调用方据此推进ProcessedRecords而不是整批失败
+func (w *MySQLWriter) WriteBatchWithMode(records []map[string]interface{}, mode WriteMode, chunkSize int) ([]RowError, error) {
+	builder := NewBatchWriteBuilder("mysql", w.tableName)
+	switch mode {
+	case WriteModeInsertIgnore:
+		builder.InsertIgnore()
+	case WriteModeUpsert:
+		builder.Upsert(nil, nil)
+	case WriteModeReplace:
+		builder.Replace()
+	default:
+		builder.Insert()
+	}
+	if chunkSize > 0 {
+		builder.BatchInTx(chunkSize)
+	}
+	return builder.Exec(w.db, records)
+}
+
+// DeleteByPrimaryKey 根据 canal 解析出的表结构和主键列删除一行，用于 binlog DELETE 事件回放
+func (w *MySQLWriter) DeleteByPrimaryKey(table *schema.Table, record map[string]interface{}) error {
+	if len(table.PKColumns) == 0 {
+		return fmt.Errorf("表 %s 没有主键，无法回放删除事件", table.Name)
+	}
+
+	conditions := make([]string, 0, len(table.PKColumns))
+	values := make([]interface{}, 0, len(table.PKColumns))
+	for _, idx := range table.PKColumns {
+		col := table.Columns[idx].Name
+		conditions = append(conditions, fmt.Sprintf("`%s` = ?", col))
+		values = append(values, record[col])
+	}
+
+	query := fmt.Sprintf("DELETE FROM `%s` WHERE %s", w.tableName, strings.Join(conditions, " AND "))
+	_, err := w.db.Exec(query, values...)
+	if err != nil {
+		return fmt.Errorf("删除行失败: %w", err)
+	}
+	return nil
+}
+
+// IndexDef 二级索引定义，用于copy_data阶段前drop、build_indexes阶段后重建
+type IndexDef struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// ListSecondaryIndexes 列出表上除主键外的二级索引定义
+func (w *MySQLWriter) ListSecondaryIndexes() ([]IndexDef, error) {
+	rows, err := w.db.Query(fmt.Sprintf("SHOW INDEX FROM `%s`", w.tableName))
+	if err != nil {
+		return nil, fmt.Errorf("查询索引失败: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("获取索引列信息失败: %w", err)
+	}
+
+	indexMap := make(map[string]*IndexDef)
+	var order []string
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, fmt.Errorf("解析索引信息失败: %w", err)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			row[col] = values[i]
+		}
+
+		keyName := fmt.Sprintf("%s", row["Key_name"])
+		if keyName == "PRIMARY" {
+			continue
+		}
+		columnName := fmt.Sprintf("%s", row["Column_name"])
+		nonUnique := fmt.Sprintf("%v", row["Non_unique"])
+
+		def, ok := indexMap[keyName]
+		if !ok {
+			def = &IndexDef{Name: keyName, Unique: nonUnique == "0"}
+			indexMap[keyName] = def
+			order = append(order, keyName)
+		}
+		def.Columns = append(def.Columns, columnName)
+	}
+
+	result := make([]IndexDef, 0, len(order))
+	for _, name := range order {
+		result = append(result, *indexMap[name])
+	}
+	return result, nil
+}
+
+// DropIndex 删除指定索引，用于大表写入前先去索引以提升拷贝速度
+func (w *MySQLWriter) DropIndex(name string) error {
+	query := fmt.Sprintf("ALTER TABLE `%s` DROP INDEX `%s`", w.tableName, name)
+	if _, err := w.db.Exec(query); err != nil {
+		return fmt.Errorf("删除索引 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// CreateIndex 按索引定义重建索引，用于copy_data完成后的build_indexes阶段
+func (w *MySQLWriter) CreateIndex(def IndexDef) error {
+	cols := make([]string, len(def.Columns))
+	for i, c := range def.Columns {
+		cols[i] = fmt.Sprintf("`%s`", c)
+	}
+	indexType := "INDEX"
+	if def.Unique {
+		indexType = "UNIQUE INDEX"
+	}
+	query := fmt.Sprintf("ALTER TABLE `%s` ADD %s `%s` (%s)", w.tableName, indexType, def.Name, strings.Join(cols, ", "))
+	if _, err := w.db.Exec(query); err != nil {
+		return fmt.Errorf("创建索引 %s 失败: %w", def.Name, err)
+	}
+	return nil
+}
+
+// CountRows 统计目标表当前行数，用于verify_rowcount阶段
+func (w *MySQLWriter) CountRows() (int64, error) {
+	var count int64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM `%s`", w.tableName)
+	if err := w.db.QueryRow(query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("统计目标表行数失败: %w", err)
+	}
+	return count, nil
+}
+
+// Checksum 计算目标表的CHECKSUM TABLE结果，用于verify_checksum阶段与源表比对
+func (w *MySQLWriter) Checksum() (string, error) {
+	return checksumTable(w.db, w.tableName)
+}
+
 // TruncateTable 清空表
 func (w *MySQLWriter) TruncateTable() error {
 	query := fmt.Sprintf("TRUNCATE TABLE `%s`", w.tableName)
@@ -185,10 +370,54 @@ func (w *MySQLWriter) CreateTableLike(sourceDB *sql.DB, sourceTable string) erro
 	return nil
 }
 
-// Close 关闭连接
+// CreateTableLikeColumns 根据标准化列信息建表，供跨方言Writer场景使用（与CreateTableLike的
+// SHOW CREATE TABLE精确复制不同，仅能还原列与主键，索引/约束等需由build_indexes阶段另行重建）
+func (w *MySQLWriter) CreateTableLikeColumns(columns []ColumnInfo) error {
+	defs := make([]string, 0, len(columns)+1)
+	var pkCols []string
+	for _, col := range columns {
+		defs = append(defs, fmt.Sprintf("`%s` %s", col.Name, mysqlColumnType(col)))
+		if col.IsPK {
+			pkCols = append(pkCols, fmt.Sprintf("`%s`", col.Name))
+		}
+	}
+	if len(pkCols) > 0 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pkCols, ", ")))
+	}
+
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s` (%s)", w.tableName, strings.Join(defs, ", "))
+	if _, err := w.db.Exec(query); err != nil {
+		return fmt.Errorf("创建表失败: %w", err)
+	}
+	return nil
+}
+
+// mysqlColumnType 将标准化逻辑类型翻译为MySQL列类型
+func mysqlColumnType(col ColumnInfo) string {
+	nullable := "NOT NULL"
+	if col.Nullable {
+		nullable = "NULL"
+	}
+	switch logicalType(col.DataType) {
+	case "bigint":
+		return "BIGINT " + nullable
+	case "decimal":
+		return "DECIMAL(20,6) " + nullable
+	case "bool":
+		return "TINYINT(1) " + nullable
+	case "datetime":
+		return "DATETIME " + nullable
+	case "text":
+		return "TEXT " + nullable
+	default:
+		return "VARCHAR(255) " + nullable
+	}
+}
+
+// Close 归还连接池引用计数；连接本身由连接池按引用计数和空闲TTL管理生命周期，此处不直接关闭
 func (w *MySQLWriter) Close() error {
-	if w.db != nil {
-		return w.db.Close()
+	if w.release != nil {
+		w.release()
 	}
 	return nil
 }
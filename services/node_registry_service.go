@@ -0,0 +1,137 @@
+package services
+
+import (
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"datatrace/config"
+	"datatrace/database"
+	"datatrace/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	nodeHeartbeatInterval = 10 * time.Second
+	nodeHeartbeatTimeout  = 30 * time.Second
+)
+
+// NodeRegistryService 维护本节点在 worker_nodes 表中的心跳，并回收失联节点占用的任务单元
+// 多个datatrace实例共享同一个数据库部署为集群时，依赖该服务互相发现和感知存活状态
+type NodeRegistryService struct {
+	nodeID   string
+	hostname string
+	ip       string
+	capacity int
+	once     sync.Once
+}
+
+var (
+	nodeRegistryInstance *NodeRegistryService
+	nodeRegistryOnce     sync.Once
+)
+
+// NewNodeRegistryService 获取节点注册服务单例
+func NewNodeRegistryService() *NodeRegistryService {
+	nodeRegistryOnce.Do(func() {
+		hostname, _ := os.Hostname()
+		nodeRegistryInstance = &NodeRegistryService{
+			nodeID:   uuid.New().String(),
+			hostname: hostname,
+			ip:       detectLocalIP(),
+			capacity: 4,
+		}
+	})
+	return nodeRegistryInstance
+}
+
+// NodeID 返回本节点在集群中的唯一标识
+func (s *NodeRegistryService) NodeID() string {
+	return s.nodeID
+}
+
+// IP 返回本节点对外的IP地址，用于 specify_ip 亲和匹配
+func (s *NodeRegistryService) IP() string {
+	return s.ip
+}
+
+// Port 返回本节点HTTP服务监听的端口，供ClusterService转发任务控制请求到本节点时使用
+func (s *NodeRegistryService) Port() int {
+	if config.GlobalConfig == nil {
+		return 0
+	}
+	return config.GlobalConfig.Server.Port
+}
+
+// Start 启动心跳和失联节点回收的后台协程，进程生命周期内只需要调用一次
+func (s *NodeRegistryService) Start() {
+	s.once.Do(func() {
+		s.heartbeat()
+		go func() {
+			ticker := time.NewTicker(nodeHeartbeatInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				s.heartbeat()
+				s.reclaimExpiredUnits()
+			}
+		}()
+	})
+}
+
+// heartbeat 写入/刷新本节点的心跳记录
+func (s *NodeRegistryService) heartbeat() {
+	node := models.WorkerNode{
+		NodeID:        s.nodeID,
+		Hostname:      s.hostname,
+		IP:            s.ip,
+		Port:          s.Port(),
+		Capacity:      s.capacity,
+		LastHeartbeat: time.Now(),
+	}
+	database.DB.Save(&node)
+}
+
+// LiveNodes 返回心跳未过期的节点列表，供ClusterService判断任务租约的持有者是否仍然存活
+func (s *NodeRegistryService) LiveNodes() ([]models.WorkerNode, error) {
+	var nodes []models.WorkerNode
+	deadline := time.Now().Add(-nodeHeartbeatTimeout)
+	err := database.DB.Where("last_heartbeat >= ?", deadline).Find(&nodes).Error
+	return nodes, err
+}
+
+// IsLive 判断指定节点当前是否仍在心跳超时时间内
+func (s *NodeRegistryService) IsLive(nodeID string) bool {
+	var node models.WorkerNode
+	deadline := time.Now().Add(-nodeHeartbeatTimeout)
+	err := database.DB.Where("node_id = ? AND last_heartbeat >= ?", nodeID, deadline).First(&node).Error
+	return err == nil
+}
+
+// reclaimExpiredUnits 将心跳过期节点占用的运行中单元重置为pending，交由其他节点重新认领
+func (s *NodeRegistryService) reclaimExpiredUnits() {
+	var expiredNodes []models.WorkerNode
+	deadline := time.Now().Add(-nodeHeartbeatTimeout)
+	database.DB.Where("last_heartbeat < ?", deadline).Find(&expiredNodes)
+
+	for _, node := range expiredNodes {
+		database.DB.Model(&models.TaskUnitRuntime{}).
+			Where("node_id = ? AND status = ?", node.NodeID, "running").
+			Updates(map[string]interface{}{
+				"status":  "pending",
+				"node_id": "",
+			})
+	}
+}
+
+// detectLocalIP 探测本机对外IP，用于节点亲和匹配；失败时回退到127.0.0.1
+func detectLocalIP() string {
+	conn, err := net.Dial("udp", "8.8.8.8:80")
+	if err != nil {
+		return "127.0.0.1"
+	}
+	defer conn.Close()
+	addr := conn.LocalAddr().(*net.UDPAddr)
+	return addr.IP.String()
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq"
+)
+
+// PostgreSQLMetadataService PostgreSQL 元数据查询服务
+type PostgreSQLMetadataService struct{}
+
+// NewPostgreSQLMetadataService 创建 PostgreSQL 元数据服务
+func NewPostgreSQLMetadataService() *PostgreSQLMetadataService {
+	return &PostgreSQLMetadataService{}
+}
+
+// GetDatabases 获取数据库列表
+func (s *PostgreSQLMetadataService) GetDatabases(host string, port int, username, password string) ([]DatabaseInfo, error) {
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=postgres sslmode=disable connect_timeout=5",
+		host, port, username, password)
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	defer db.Close()
+
+	query := `
+		SELECT d.datname as name,
+			(SELECT COUNT(*) FROM information_schema.tables t
+			 WHERE t.table_catalog = d.datname AND t.table_schema = 'public' AND t.table_type = 'BASE TABLE') as table_count
+		FROM pg_database d
+		WHERE d.datistemplate = false
+		ORDER BY d.datname
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	var databases []DatabaseInfo
+	for rows.Next() {
+		var db DatabaseInfo
+		if err := rows.Scan(&db.Name, &db.TableCount); err != nil {
+			return nil, err
+		}
+		databases = append(databases, db)
+	}
+
+	return databases, nil
+}
+
+// GetTables 获取指定数据库的表列表
+func (s *PostgreSQLMetadataService) GetTables(host string, port int, username, password, database string) ([]TableInfo, error) {
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable connect_timeout=5",
+		host, port, username, password, database)
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	defer db.Close()
+
+	query := `
+		SELECT c.relname as name, COALESCE(obj_description(c.oid), '') as comment
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relkind = 'r'
+		ORDER BY c.relname
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []TableInfo
+	for rows.Next() {
+		var table TableInfo
+		if err := rows.Scan(&table.Name, &table.Comment); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+// GetSchemas 获取指定数据库下的模式列表（排除系统模式）
+func (s *PostgreSQLMetadataService) GetSchemas(host string, port int, username, password, database string) ([]string, error) {
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable connect_timeout=5",
+		host, port, username, password, database)
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	defer db.Close()
+
+	query := `
+		SELECT schema_name FROM information_schema.schemata
+		WHERE schema_name NOT IN ('pg_catalog', 'information_schema')
+		ORDER BY schema_name
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var schema string
+		if err := rows.Scan(&schema); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema)
+	}
+
+	return schemas, nil
+}
+
+// GetColumns 获取指定表（public模式）的列信息
+func (s *PostgreSQLMetadataService) GetColumns(host string, port int, username, password, database, table string) ([]ColumnInfo, error) {
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable connect_timeout=5",
+		host, port, username, password, database)
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	defer db.Close()
+
+	query := `
+		SELECT c.column_name, c.data_type, c.is_nullable = 'YES',
+			EXISTS (
+				SELECT 1 FROM information_schema.key_column_usage k
+				JOIN information_schema.table_constraints tc ON tc.constraint_name = k.constraint_name
+				WHERE tc.constraint_type = 'PRIMARY KEY' AND k.table_name = c.table_name AND k.column_name = c.column_name
+			)
+		FROM information_schema.columns c
+		WHERE c.table_schema = 'public' AND c.table_name = $1
+		ORDER BY c.ordinal_position
+	`
+
+	rows, err := db.Query(query, table)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var col ColumnInfo
+		if err := rows.Scan(&col.Name, &col.DataType, &col.Nullable, &col.IsPK); err != nil {
+			return nil, err
+		}
+		columns = append(columns, col)
+	}
+
+	return columns, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+// PostgreSQLWriter PostgreSQL数据写入器
+type PostgreSQLWriter struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgreSQLWriter 创建PostgreSQL写入器
+func NewPostgreSQLWriter(cfg WriterConfig) (*PostgreSQLWriter, error) {
+	if err := ValidateTableName(cfg.Table); err != nil {
+		return nil, fmt.Errorf("表名校验失败: %w", err)
+	}
+
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+		cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.Database)
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("连接数据库失败: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("数据库连接测试失败: %w", err)
+	}
+
+	return &PostgreSQLWriter{db: db, tableName: cfg.Table}, nil
+}
+
+// WriteBatch 批量写入数据
+func (w *PostgreSQLWriter) WriteBatch(records []map[string]interface{}) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var columns []string
+	for col := range records[0] {
+		columns = append(columns, col)
+	}
+
+	placeholders := make([]string, len(records))
+	values := make([]interface{}, 0, len(records)*len(columns))
+	argN := 1
+	for i, record := range records {
+		rowPlaceholders := make([]string, len(columns))
+		for j := range columns {
+			rowPlaceholders[j] = fmt.Sprintf("$%d", argN)
+			argN++
+		}
+		placeholders[i] = fmt.Sprintf("(%s)", strings.Join(rowPlaceholders, ", "))
+		for _, col := range columns {
+			values = append(values, record[col])
+		}
+	}
+
+	columnNames := make([]string, len(columns))
+	for i, col := range columns {
+		columnNames[i] = fmt.Sprintf("%q", col)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %q (%s) VALUES %s",
+		w.tableName, strings.Join(columnNames, ", "), strings.Join(placeholders, ", "))
+
+	if _, err := w.db.Exec(query, values...); err != nil {
+		return fmt.Errorf("批量插入失败: %w", err)
+	}
+	return nil
+}
+
+// CreateTableLike 根据标准化列信息建表
+func (w *PostgreSQLWriter) CreateTableLike(columns []ColumnInfo) error {
+	defs := make([]string, 0, len(columns)+1)
+	var pkCols []string
+	for _, col := range columns {
+		defs = append(defs, fmt.Sprintf("%q %s", col.Name, postgresColumnType(col)))
+		if col.IsPK {
+			pkCols = append(pkCols, fmt.Sprintf("%q", col.Name))
+		}
+	}
+	if len(pkCols) > 0 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pkCols, ", ")))
+	}
+
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %q (%s)", w.tableName, strings.Join(defs, ", "))
+	if _, err := w.db.Exec(query); err != nil {
+		return fmt.Errorf("创建表失败: %w", err)
+	}
+	return nil
+}
+
+// postgresColumnType 将标准化逻辑类型翻译为PostgreSQL列类型
+func postgresColumnType(col ColumnInfo) string {
+	nullable := "NOT NULL"
+	if col.Nullable {
+		nullable = "NULL"
+	}
+	switch logicalType(col.DataType) {
+	case "bigint":
+		return "BIGINT " + nullable
+	case "decimal":
+		return "NUMERIC(20,6) " + nullable
+	case "bool":
+		return "BOOLEAN " + nullable
+	case "datetime":
+		return "TIMESTAMP " + nullable
+	case "text":
+		return "TEXT " + nullable
+	default:
+		return "VARCHAR(255) " + nullable
+	}
+}
+
+// TruncateTable 清空表
+func (w *PostgreSQLWriter) TruncateTable() error {
+	query := fmt.Sprintf("TRUNCATE TABLE %q", w.tableName)
+	if _, err := w.db.Exec(query); err != nil {
+		return fmt.Errorf("清空表失败: %w", err)
+	}
+	return nil
+}
+
+// DropTable 删除表
+func (w *PostgreSQLWriter) DropTable() error {
+	query := fmt.Sprintf("DROP TABLE IF EXISTS %q", w.tableName)
+	if _, err := w.db.Exec(query); err != nil {
+		return fmt.Errorf("删除表失败: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭连接
+func (w *PostgreSQLWriter) Close() error {
+	if w.db != nil {
+		return w.db.Close()
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// progressCoalesceInterval 同一任务两次进度推送之间的最小间隔，超出该窗口内的重复通知被合并为一次
+const progressCoalesceInterval = 200 * time.Millisecond
+
+// pendingProgress 某个任务的合并状态：是否已有待发送的更新、以及上次实际推送的时间
+type pendingProgress struct {
+	mu        sync.Mutex
+	lastFlush time.Time
+	timer     *time.Timer
+}
+
+// ProgressBroker 按任务合并SyncEngine.Worker产生的高频进度变化，每个任务最多每
+// progressCoalesceInterval推送一次，减少Worker写入量大时对TaskEventBus/订阅者的压力；
+// 实际的事件分发仍然复用TaskSSEService/TaskEventBus，这里只做节流
+type ProgressBroker struct {
+	sseService *TaskSSEService
+
+	mu      sync.Mutex
+	pending map[string]*pendingProgress
+}
+
+var (
+	progressBrokerInstance *ProgressBroker
+	progressBrokerOnce     sync.Once
+)
+
+// NewProgressBroker 获取进度合并广播器单例
+func NewProgressBroker() *ProgressBroker {
+	progressBrokerOnce.Do(func() {
+		progressBrokerInstance = &ProgressBroker{
+			sseService: NewTaskSSEService(),
+			pending:    make(map[string]*pendingProgress),
+		}
+	})
+	return progressBrokerInstance
+}
+
+// Publish 通知taskID的进度发生了变化。窗口期内的第一次调用立即推送，之后的调用会被合并，
+// 延迟到窗口结束时只推送一次最新状态，而不是逐条转发
+func (b *ProgressBroker) Publish(taskID string) {
+	b.mu.Lock()
+	p, ok := b.pending[taskID]
+	if !ok {
+		p = &pendingProgress{}
+		b.pending[taskID] = p
+	}
+	b.mu.Unlock()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.timer != nil {
+		// 窗口内已经有一次待触发的合并推送，本次变化会被它覆盖，无需重复调度
+		return
+	}
+
+	elapsed := time.Since(p.lastFlush)
+	if p.lastFlush.IsZero() || elapsed >= progressCoalesceInterval {
+		p.lastFlush = time.Now()
+		b.sseService.BroadcastProgressUpdate(taskID)
+		return
+	}
+
+	wait := progressCoalesceInterval - elapsed
+	p.timer = time.AfterFunc(wait, func() {
+		p.mu.Lock()
+		p.timer = nil
+		p.lastFlush = time.Now()
+		p.mu.Unlock()
+		b.sseService.BroadcastProgressUpdate(taskID)
+	})
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// QueryRequest 结构化的即席查询请求：select/where/order_by按列名而非任意表达式描述，
+// limit/offset控制返回的行数和偏移量，配合QueryTranslator编译为参数化SQL
+type QueryRequest struct {
+	Table   string                 `json:"table"`    // 要查询的表名
+	Select  []string               `json:"select"`   // 要查询的列，为空表示SELECT *
+	Where   map[string]interface{} `json:"where"`    // 等值过滤条件，key为列名，value为比较值，不支持表达式
+	OrderBy []string               `json:"order_by"` // 排序列，按给定顺序ASC排序
+	Limit   int                    `json:"limit"`    // 返回行数上限，超过maxLimit会被截断
+	Offset  int                    `json:"offset"`   // 跳过的行数
+}
+
+// QueryTranslator 把QueryRequest编译成对应方言的参数化SELECT语句。表名/列名只接受经过
+// ValidateTableName/ValidateColumnName校验的标识符，取值一律走占位符，不拼接任意表达式，
+// 用于即席预览接口防止SQL注入
+type QueryTranslator struct {
+	dialect  string // mysql/postgresql/sqlserver
+	maxLimit int
+}
+
+// NewQueryTranslator 创建翻译器，maxLimit通常取自config.GlobalConfig.Query.MaxPreviewLimit
+func NewQueryTranslator(dialect string, maxLimit int) *QueryTranslator {
+	return &QueryTranslator{dialect: dialect, maxLimit: maxLimit}
+}
+
+// quoteIdent 按方言给标识符加引号，与BatchWriteBuilder保持一致
+func (t *QueryTranslator) quoteIdent(name string) string {
+	switch t.dialect {
+	case "postgresql":
+		return fmt.Sprintf(`"%s"`, name)
+	case "sqlserver":
+		return fmt.Sprintf("[%s]", name)
+	default:
+		return fmt.Sprintf("`%s`", name)
+	}
+}
+
+// placeholder 按方言生成第n个(从1开始)占位符
+func (t *QueryTranslator) placeholder(n int) string {
+	switch t.dialect {
+	case "postgresql":
+		return fmt.Sprintf("$%d", n)
+	case "sqlserver":
+		return fmt.Sprintf("@p%d", n)
+	default:
+		return "?"
+	}
+}
+
+// Build 校验并编译req为一条SELECT语句，返回SQL文本与按位置对应的参数列表
+func (t *QueryTranslator) Build(req *QueryRequest) (string, []interface{}, error) {
+	if err := ValidateTableName(req.Table); err != nil {
+		return "", nil, err
+	}
+
+	columns := "*"
+	if len(req.Select) > 0 {
+		quoted := make([]string, len(req.Select))
+		for i, col := range req.Select {
+			if err := ValidateColumnName(col); err != nil {
+				return "", nil, err
+			}
+			quoted[i] = t.quoteIdent(col)
+		}
+		columns = strings.Join(quoted, ", ")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "SELECT %s FROM %s", columns, t.quoteIdent(req.Table))
+
+	args := make([]interface{}, 0, len(req.Where)+2)
+	if len(req.Where) > 0 {
+		keys := make([]string, 0, len(req.Where))
+		for k := range req.Where {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys) // 保证生成的SQL文本稳定，便于排查问题
+
+		conditions := make([]string, 0, len(keys))
+		for _, col := range keys {
+			if err := ValidateColumnName(col); err != nil {
+				return "", nil, err
+			}
+			args = append(args, req.Where[col])
+			conditions = append(conditions, fmt.Sprintf("%s = %s", t.quoteIdent(col), t.placeholder(len(args))))
+		}
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(conditions, " AND "))
+	}
+
+	hasOrderBy := len(req.OrderBy) > 0
+	if hasOrderBy {
+		quoted := make([]string, len(req.OrderBy))
+		for i, col := range req.OrderBy {
+			if err := ValidateColumnName(col); err != nil {
+				return "", nil, err
+			}
+			quoted[i] = t.quoteIdent(col)
+		}
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(quoted, ", "))
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > t.maxLimit {
+		limit = t.maxLimit
+	}
+
+	if t.dialect == "sqlserver" {
+		// T-SQL没有LIMIT关键字，分页用OFFSET ... FETCH NEXT ... ROWS ONLY，且该子句要求必须有ORDER BY
+		if !hasOrderBy {
+			sb.WriteString(" ORDER BY (SELECT NULL)")
+		}
+		args = append(args, req.Offset)
+		fmt.Fprintf(&sb, " OFFSET %s ROWS", t.placeholder(len(args)))
+		args = append(args, limit)
+		fmt.Fprintf(&sb, " FETCH NEXT %s ROWS ONLY", t.placeholder(len(args)))
+		return sb.String(), args, nil
+	}
+
+	args = append(args, limit)
+	fmt.Fprintf(&sb, " LIMIT %s", t.placeholder(len(args)))
+
+	if req.Offset > 0 {
+		args = append(args, req.Offset)
+		fmt.Fprintf(&sb, " OFFSET %s", t.placeholder(len(args)))
+	}
+
+	return sb.String(), args, nil
+}
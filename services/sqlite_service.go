@@ -0,0 +1,97 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteMetadataService SQLite 元数据查询服务；SQLite为文件型数据库，host/port参数在此不使用
+type SQLiteMetadataService struct{}
+
+// NewSQLiteMetadataService 创建 SQLite 元数据服务
+func NewSQLiteMetadataService() *SQLiteMetadataService {
+	return &SQLiteMetadataService{}
+}
+
+// GetDatabases SQLite一个文件即一个数据库，没有多库概念，返回文件本身作为唯一"数据库"
+func (s *SQLiteMetadataService) GetDatabases(host string, port int, username, password string) ([]DatabaseInfo, error) {
+	db, err := sql.Open("sqlite3", host)
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'").Scan(&count); err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+
+	return []DatabaseInfo{{Name: host, TableCount: count}}, nil
+}
+
+// GetSchemas SQLite只有唯一的main模式
+func (s *SQLiteMetadataService) GetSchemas(host string, port int, username, password, database string) ([]string, error) {
+	return []string{"main"}, nil
+}
+
+// GetTables 获取表列表
+func (s *SQLiteMetadataService) GetTables(host string, port int, username, password, database string) ([]TableInfo, error) {
+	db, err := sql.Open("sqlite3", host)
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []TableInfo
+	for rows.Next() {
+		var table TableInfo
+		if err := rows.Scan(&table.Name); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+// GetColumns 通过 PRAGMA table_info 获取列信息
+func (s *SQLiteMetadataService) GetColumns(host string, port int, username, password, database, table string) ([]ColumnInfo, error) {
+	db, err := sql.Open("sqlite3", host)
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%q)", table))
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var cid int
+		var name, dataType string
+		var notNull, pk int
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &dataType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, err
+		}
+		columns = append(columns, ColumnInfo{
+			Name:     name,
+			DataType: dataType,
+			Nullable: notNull == 0,
+			IsPK:     pk > 0,
+		})
+	}
+
+	return columns, nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteWriter SQLite数据写入器；Host字段复用为数据库文件路径
+type SQLiteWriter struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewSQLiteWriter 创建SQLite写入器
+func NewSQLiteWriter(cfg WriterConfig) (*SQLiteWriter, error) {
+	if err := ValidateTableName(cfg.Table); err != nil {
+		return nil, fmt.Errorf("表名校验失败: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", cfg.Host)
+	if err != nil {
+		return nil, fmt.Errorf("打开数据库文件失败: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("数据库连接测试失败: %w", err)
+	}
+
+	return &SQLiteWriter{db: db, tableName: cfg.Table}, nil
+}
+
+// WriteBatch 批量写入数据
+func (w *SQLiteWriter) WriteBatch(records []map[string]interface{}) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var columns []string
+	for col := range records[0] {
+		columns = append(columns, col)
+	}
+
+	placeholders := make([]string, len(records))
+	values := make([]interface{}, 0, len(records)*len(columns))
+	for i, record := range records {
+		rowPlaceholders := make([]string, len(columns))
+		for j := range columns {
+			rowPlaceholders[j] = "?"
+		}
+		placeholders[i] = fmt.Sprintf("(%s)", strings.Join(rowPlaceholders, ", "))
+		for _, col := range columns {
+			values = append(values, record[col])
+		}
+	}
+
+	columnNames := make([]string, len(columns))
+	for i, col := range columns {
+		columnNames[i] = fmt.Sprintf("%q", col)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %q (%s) VALUES %s",
+		w.tableName, strings.Join(columnNames, ", "), strings.Join(placeholders, ", "))
+
+	if _, err := w.db.Exec(query, values...); err != nil {
+		return fmt.Errorf("批量插入失败: %w", err)
+	}
+	return nil
+}
+
+// CreateTableLike 根据标准化列信息建表
+func (w *SQLiteWriter) CreateTableLike(columns []ColumnInfo) error {
+	defs := make([]string, 0, len(columns)+1)
+	var pkCols []string
+	for _, col := range columns {
+		defs = append(defs, fmt.Sprintf("%q %s", col.Name, sqliteColumnType(col)))
+		if col.IsPK {
+			pkCols = append(pkCols, fmt.Sprintf("%q", col.Name))
+		}
+	}
+	if len(pkCols) > 0 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pkCols, ", ")))
+	}
+
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %q (%s)", w.tableName, strings.Join(defs, ", "))
+	if _, err := w.db.Exec(query); err != nil {
+		return fmt.Errorf("创建表失败: %w", err)
+	}
+	return nil
+}
+
+// sqliteColumnType 将标准化逻辑类型翻译为SQLite的存储类别（SQLite采用动态类型，仅需类型亲和性）
+func sqliteColumnType(col ColumnInfo) string {
+	nullable := "NOT NULL"
+	if col.Nullable {
+		nullable = "NULL"
+	}
+	switch logicalType(col.DataType) {
+	case "bigint":
+		return "INTEGER " + nullable
+	case "decimal":
+		return "REAL " + nullable
+	case "bool":
+		return "INTEGER " + nullable
+	case "datetime":
+		return "TEXT " + nullable
+	case "text":
+		return "TEXT " + nullable
+	default:
+		return "TEXT " + nullable
+	}
+}
+
+// TruncateTable 清空表
+func (w *SQLiteWriter) TruncateTable() error {
+	query := fmt.Sprintf("DELETE FROM %q", w.tableName)
+	if _, err := w.db.Exec(query); err != nil {
+		return fmt.Errorf("清空表失败: %w", err)
+	}
+	return nil
+}
+
+// DropTable 删除表
+func (w *SQLiteWriter) DropTable() error {
+	query := fmt.Sprintf("DROP TABLE IF EXISTS %q", w.tableName)
+	if _, err := w.db.Exec(query); err != nil {
+		return fmt.Errorf("删除表失败: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭连接
+func (w *SQLiteWriter) Close() error {
+	if w.db != nil {
+		return w.db.Close()
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/denisenkom/go-mssqldb"
+)
+
+// SQLServerMetadataService SQL Server 元数据查询服务
+type SQLServerMetadataService struct{}
+
+// NewSQLServerMetadataService 创建 SQL Server 元数据服务
+func NewSQLServerMetadataService() *SQLServerMetadataService {
+	return &SQLServerMetadataService{}
+}
+
+// GetDatabases 获取数据库列表（排除内置系统库）
+func (s *SQLServerMetadataService) GetDatabases(host string, port int, username, password string) ([]DatabaseInfo, error) {
+	dsn := fmt.Sprintf("sqlserver://%s:%s@%s:%d?connection+timeout=5", username, password, host, port)
+
+	db, err := sql.Open("sqlserver", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	defer db.Close()
+
+	query := `
+		SELECT d.name as name,
+			(SELECT COUNT(*) FROM sys.tables) as table_count
+		FROM sys.databases d
+		WHERE d.database_id > 4
+		ORDER BY d.name
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	var databases []DatabaseInfo
+	for rows.Next() {
+		var db DatabaseInfo
+		if err := rows.Scan(&db.Name, &db.TableCount); err != nil {
+			return nil, err
+		}
+		databases = append(databases, db)
+	}
+
+	return databases, nil
+}
+
+// GetTables 获取指定数据库的表列表
+func (s *SQLServerMetadataService) GetTables(host string, port int, username, password, database string) ([]TableInfo, error) {
+	dsn := fmt.Sprintf("sqlserver://%s:%s@%s:%d?database=%s&connection+timeout=5", username, password, host, port, database)
+
+	db, err := sql.Open("sqlserver", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	defer db.Close()
+
+	query := `
+		SELECT t.name as name, CAST(ISNULL(p.value, '') AS NVARCHAR(4000)) as comment
+		FROM sys.tables t
+		LEFT JOIN sys.extended_properties p ON p.major_id = t.object_id AND p.minor_id = 0 AND p.name = 'MS_Description'
+		ORDER BY t.name
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []TableInfo
+	for rows.Next() {
+		var table TableInfo
+		if err := rows.Scan(&table.Name, &table.Comment); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+// GetSchemas 获取指定数据库下的模式列表
+func (s *SQLServerMetadataService) GetSchemas(host string, port int, username, password, database string) ([]string, error) {
+	dsn := fmt.Sprintf("sqlserver://%s:%s@%s:%d?database=%s&connection+timeout=5", username, password, host, port, database)
+
+	db, err := sql.Open("sqlserver", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT name FROM sys.schemas WHERE schema_id < 16384 ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var schema string
+		if err := rows.Scan(&schema); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema)
+	}
+
+	return schemas, nil
+}
+
+// GetColumns 获取指定表的列信息，通过 INFORMATION_SCHEMA.COLUMNS 内省（替代 SQL Server 不支持的 SHOW CREATE TABLE）
+func (s *SQLServerMetadataService) GetColumns(host string, port int, username, password, database, table string) ([]ColumnInfo, error) {
+	dsn := fmt.Sprintf("sqlserver://%s:%s@%s:%d?database=%s&connection+timeout=5", username, password, host, port, database)
+
+	db, err := sql.Open("sqlserver", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	defer db.Close()
+
+	query := `
+		SELECT c.COLUMN_NAME, c.DATA_TYPE, c.IS_NULLABLE = 'YES',
+			CASE WHEN pk.COLUMN_NAME IS NOT NULL THEN 1 ELSE 0 END
+		FROM INFORMATION_SCHEMA.COLUMNS c
+		LEFT JOIN (
+			SELECT ku.COLUMN_NAME
+			FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+			JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE ku ON tc.CONSTRAINT_NAME = ku.CONSTRAINT_NAME
+			WHERE tc.CONSTRAINT_TYPE = 'PRIMARY KEY' AND ku.TABLE_NAME = @p1
+		) pk ON pk.COLUMN_NAME = c.COLUMN_NAME
+		WHERE c.TABLE_NAME = @p1
+		ORDER BY c.ORDINAL_POSITION
+	`
+
+	rows, err := db.Query(query, table)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var col ColumnInfo
+		if err := rows.Scan(&col.Name, &col.DataType, &col.Nullable, &col.IsPK); err != nil {
+			return nil, err
+		}
+		columns = append(columns, col)
+	}
+
+	return columns, nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/denisenkom/go-mssqldb"
+)
+
+// SQLServerWriter SQL Server数据写入器
+type SQLServerWriter struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewSQLServerWriter 创建SQL Server写入器
+func NewSQLServerWriter(cfg WriterConfig) (*SQLServerWriter, error) {
+	if err := ValidateTableName(cfg.Table); err != nil {
+		return nil, fmt.Errorf("表名校验失败: %w", err)
+	}
+
+	dsn := fmt.Sprintf("sqlserver://%s:%s@%s:%d?database=%s", cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.Database)
+
+	db, err := sql.Open("sqlserver", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("连接数据库失败: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("数据库连接测试失败: %w", err)
+	}
+
+	return &SQLServerWriter{db: db, tableName: cfg.Table}, nil
+}
+
+// WriteBatch 批量写入数据
+func (w *SQLServerWriter) WriteBatch(records []map[string]interface{}) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var columns []string
+	for col := range records[0] {
+		columns = append(columns, col)
+	}
+
+	placeholders := make([]string, len(records))
+	values := make([]interface{}, 0, len(records)*len(columns))
+	argN := 1
+	for i, record := range records {
+		rowPlaceholders := make([]string, len(columns))
+		for j := range columns {
+			rowPlaceholders[j] = fmt.Sprintf("@p%d", argN)
+			argN++
+		}
+		placeholders[i] = fmt.Sprintf("(%s)", strings.Join(rowPlaceholders, ", "))
+		for _, col := range columns {
+			values = append(values, record[col])
+		}
+	}
+
+	columnNames := make([]string, len(columns))
+	for i, col := range columns {
+		columnNames[i] = fmt.Sprintf("[%s]", col)
+	}
+
+	query := fmt.Sprintf("INSERT INTO [%s] (%s) VALUES %s",
+		w.tableName, strings.Join(columnNames, ", "), strings.Join(placeholders, ", "))
+
+	if _, err := w.db.Exec(query, values...); err != nil {
+		return fmt.Errorf("批量插入失败: %w", err)
+	}
+	return nil
+}
+
+// CreateTableLike 根据标准化列信息建表；SQL Server用 [ ] 标识符引用替代MySQL的反引号
+func (w *SQLServerWriter) CreateTableLike(columns []ColumnInfo) error {
+	defs := make([]string, 0, len(columns)+1)
+	var pkCols []string
+	for _, col := range columns {
+		defs = append(defs, fmt.Sprintf("[%s] %s", col.Name, sqlServerColumnType(col)))
+		if col.IsPK {
+			pkCols = append(pkCols, fmt.Sprintf("[%s]", col.Name))
+		}
+	}
+	if len(pkCols) > 0 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pkCols, ", ")))
+	}
+
+	query := fmt.Sprintf(
+		"IF NOT EXISTS (SELECT 1 FROM sys.tables WHERE name = '%s') CREATE TABLE [%s] (%s)",
+		w.tableName, w.tableName, strings.Join(defs, ", "))
+	if _, err := w.db.Exec(query); err != nil {
+		return fmt.Errorf("创建表失败: %w", err)
+	}
+	return nil
+}
+
+// sqlServerColumnType 将标准化逻辑类型翻译为SQL Server列类型
+func sqlServerColumnType(col ColumnInfo) string {
+	nullable := "NOT NULL"
+	if col.Nullable {
+		nullable = "NULL"
+	}
+	switch logicalType(col.DataType) {
+	case "bigint":
+		return "BIGINT " + nullable
+	case "decimal":
+		return "DECIMAL(20,6) " + nullable
+	case "bool":
+		return "BIT " + nullable
+	case "datetime":
+		return "DATETIME2 " + nullable
+	case "text":
+		return "NVARCHAR(MAX) " + nullable
+	default:
+		return "NVARCHAR(255) " + nullable
+	}
+}
+
+// TruncateTable 清空表
+func (w *SQLServerWriter) TruncateTable() error {
+	query := fmt.Sprintf("TRUNCATE TABLE [%s]", w.tableName)
+	if _, err := w.db.Exec(query); err != nil {
+		return fmt.Errorf("清空表失败: %w", err)
+	}
+	return nil
+}
+
+// DropTable 删除表
+func (w *SQLServerWriter) DropTable() error {
+	query := fmt.Sprintf("IF EXISTS (SELECT 1 FROM sys.tables WHERE name = '%s') DROP TABLE [%s]", w.tableName, w.tableName)
+	if _, err := w.db.Exec(query); err != nil {
+		return fmt.Errorf("删除表失败: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭连接
+func (w *SQLServerWriter) Close() error {
+	if w.db != nil {
+		return w.db.Close()
+	}
+	return nil
+}
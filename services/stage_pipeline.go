@@ -0,0 +1,246 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"datatrace/database"
+	"datatrace/models"
+
+	"github.com/google/uuid"
+)
+
+// 固定的单元同步流水线阶段，顺序固定：建立连接/建库 -> 清空或建表 -> 拷贝数据 -> 重建索引 -> 校验行数 -> 校验校验和
+const (
+	StagePrepareSchema    = "prepare_schema"
+	StageTruncateOrCreate = "truncate_or_create"
+	StageCopyData         = "copy_data"
+	StageBuildIndexes     = "build_indexes"
+	StageVerifyRowcount   = "verify_rowcount"
+	StageVerifyChecksum   = "verify_checksum"
+)
+
+// stagePipeline 阶段流水线顺序，用于初始化占位记录和排序展示
+var stagePipeline = []string{
+	StagePrepareSchema,
+	StageTruncateOrCreate,
+	StageCopyData,
+	StageBuildIndexes,
+	StageVerifyRowcount,
+	StageVerifyChecksum,
+}
+
+// stageContext 单元同步过程中跨阶段共享的状态：已建立的连接、解析出的库表名、本次拷贝前删除的索引
+type stageContext struct {
+	task           *models.SyncTask
+	config         *TaskConfig
+	sourceDB       string
+	sourceTable    string
+	targetDB       string
+	targetTable    string
+	reader         *MySQLReader
+	writer         *MySQLWriter
+	droppedIndexes []IndexDef
+}
+
+// ensureUnitStages 为单元的六个固定阶段建立占位记录（已存在则跳过），用于前端渲染里程碑时间线
+func (e *SyncEngine) ensureUnitStages(taskID string, unit *models.TaskUnitRuntime) {
+	now := time.Now()
+	for _, name := range stagePipeline {
+		var existing models.TaskUnitStage
+		err := database.DB.Where("unit_id = ? AND stage_name = ?", unit.ID, name).First(&existing).Error
+		if err == nil {
+			continue
+		}
+		database.DB.Create(&models.TaskUnitStage{
+			ID:        uuid.New().String(),
+			TaskID:    taskID,
+			UnitID:    unit.ID,
+			StageName: name,
+			Status:    "pending",
+			PlannedAt: now,
+		})
+	}
+}
+
+// runStage 以阶段为粒度执行并记录进入/退出：标记running -> 执行fn -> 标记completed/failed，
+// 并通过SSE推送阶段流转，供前端渲染里程碑时间线。stage独立持久化状态，支持单独重跑
+func (e *SyncEngine) runStage(taskID string, unit *models.TaskUnitRuntime, stageName string, fn func() error) error {
+	startedAt := time.Now()
+	database.DB.Model(&models.TaskUnitStage{}).
+		Where("unit_id = ? AND stage_name = ?", unit.ID, stageName).
+		Updates(map[string]interface{}{"status": "running", "started_at": startedAt, "error": ""})
+	e.broadcastStage(taskID, unit.ID, stageName, "running")
+
+	err := fn()
+
+	completedAt := time.Now()
+	if err != nil {
+		database.DB.Model(&models.TaskUnitStage{}).
+			Where("unit_id = ? AND stage_name = ?", unit.ID, stageName).
+			Updates(map[string]interface{}{"status": "failed", "completed_at": completedAt, "error": err.Error()})
+		e.broadcastStage(taskID, unit.ID, stageName, "failed")
+		return err
+	}
+
+	database.DB.Model(&models.TaskUnitStage{}).
+		Where("unit_id = ? AND stage_name = ?", unit.ID, stageName).
+		Updates(map[string]interface{}{"status": "completed", "completed_at": completedAt})
+	e.broadcastStage(taskID, unit.ID, stageName, "completed")
+	return nil
+}
+
+// skipStage 标记某阶段不适用于当前同步模式（例如binlog/增量模式下的索引重建或行数/校验和校验）
+func (e *SyncEngine) skipStage(taskID string, unit *models.TaskUnitRuntime, stageName string) {
+	database.DB.Model(&models.TaskUnitStage{}).
+		Where("unit_id = ? AND stage_name = ?", unit.ID, stageName).
+		Updates(map[string]interface{}{"status": "skipped", "completed_at": time.Now()})
+	e.broadcastStage(taskID, unit.ID, stageName, "skipped")
+}
+
+// broadcastStage 推送阶段流转事件到SSE总线
+func (e *SyncEngine) broadcastStage(taskID, unitID, stageName, status string) {
+	e.sseService.bus.Publish(taskID, EventStage, map[string]string{
+		"unit_id":    unitID,
+		"stage_name": stageName,
+		"status":     status,
+	})
+}
+
+// stagePrepareSchema prepare_schema 阶段：解密连接密码、建立reader/writer连接、确保目标库存在
+func (e *SyncEngine) stagePrepareSchema(taskID string, sc *stageContext) error {
+	sourcePassword, err := e.dsService.crypto.Decrypt(sc.task.SourceConn.Password)
+	if err != nil {
+		return fmt.Errorf("解密源数据库密码失败: %w", err)
+	}
+	targetPassword, err := e.dsService.crypto.Decrypt(sc.task.TargetConn.Password)
+	if err != nil {
+		return fmt.Errorf("解密目标数据库密码失败: %w", err)
+	}
+
+	reader, err := NewMySQLReader(
+		sc.task.SourceConn.Host, sc.task.SourceConn.Port, sc.task.SourceConn.Username, sourcePassword,
+		sc.sourceDB, sc.sourceTable, sc.config.SyncConfig.BatchSize,
+	)
+	if err != nil {
+		return fmt.Errorf("创建Reader失败: %w", err)
+	}
+	sc.reader = reader
+
+	sourceCharset, sourceCollation, err := reader.GetDatabaseCharset(sc.sourceDB)
+	if err != nil {
+		e.logService.Warning(taskID, fmt.Sprintf("获取源数据库字符集失败，使用默认值: %v", err))
+	}
+	e.logService.Info(taskID, fmt.Sprintf("源数据库 %s 字符集: %s, 排序规则: %s", sc.sourceDB, sourceCharset, sourceCollation))
+
+	created, err := CreateDatabaseIfNotExists(
+		sc.task.TargetConn.Host, sc.task.TargetConn.Port, sc.task.TargetConn.Username, targetPassword,
+		sc.targetDB, sourceCharset, sourceCollation,
+	)
+	if err != nil {
+		reader.Close()
+		sc.reader = nil
+		return fmt.Errorf("创建目标数据库失败: %w", err)
+	}
+	e.logService.Info(taskID, fmt.Sprintf("目标数据库 %s 已就绪", sc.targetDB))
+
+	if created {
+		e.logService.AddLog(taskID, "info", fmt.Sprintf("创建数据库: %s", sc.targetDB), "create")
+		e.sseService.BroadcastLogUpdate(taskID, []TaskLog{{
+			Time:     formatLogTime(time.Now()),
+			Level:    "info",
+			Message:  fmt.Sprintf("创建数据库: %s", sc.targetDB),
+			Category: "create",
+		}})
+	}
+
+	writer, err := NewMySQLWriter(
+		sc.task.TargetConn.Host, sc.task.TargetConn.Port, sc.task.TargetConn.Username, targetPassword,
+		sc.targetDB, sc.targetTable,
+	)
+	if err != nil {
+		reader.Close()
+		sc.reader = nil
+		return fmt.Errorf("创建Writer失败: %w", err)
+	}
+	sc.writer = writer
+
+	return nil
+}
+
+// verifyRowcount verify_rowcount 阶段：比对源表与目标表当前行数是否一致
+func (e *SyncEngine) verifyRowcount(sc *stageContext) error {
+	targetCount, err := sc.writer.CountRows()
+	if err != nil {
+		return err
+	}
+	sourceCount := sc.reader.GetTotalCount()
+	if targetCount != sourceCount {
+		return fmt.Errorf("行数不一致: 源 %d, 目标 %d", sourceCount, targetCount)
+	}
+	return nil
+}
+
+// verifyChecksum verify_checksum 阶段：比对源表与目标表的 CHECKSUM TABLE 结果
+func (e *SyncEngine) verifyChecksum(sc *stageContext) error {
+	sourceChecksum, err := sc.reader.Checksum()
+	if err != nil {
+		return err
+	}
+	targetChecksum, err := sc.writer.Checksum()
+	if err != nil {
+		return err
+	}
+	if sourceChecksum != targetChecksum {
+		return fmt.Errorf("校验和不一致: 源 %s, 目标 %s", sourceChecksum, targetChecksum)
+	}
+	return nil
+}
+
+// RetryStage 单独重跑某个单元的某一阶段，无需重跑整张表的数据拷贝（例如verify_checksum失败后重新校验）
+func (e *SyncEngine) RetryStage(taskID string, unit *models.TaskUnitRuntime, stageName string) error {
+	var task models.SyncTask
+	if err := database.DB.Preload("SourceConn").Preload("TargetConn").First(&task, "id = ?", taskID).Error; err != nil {
+		return fmt.Errorf("查询任务失败: %w", err)
+	}
+
+	var config TaskConfig
+	if err := json.Unmarshal([]byte(task.Config), &config); err != nil {
+		return fmt.Errorf("解析配置失败: %w", err)
+	}
+
+	sourceDB, sourceTable, targetDB, targetTable, err := e.parseUnitName(unit.UnitName, &config)
+	if err != nil {
+		return fmt.Errorf("解析表名失败: %w", err)
+	}
+
+	sc := &stageContext{task: &task, config: &config, sourceDB: sourceDB, sourceTable: sourceTable, targetDB: targetDB, targetTable: targetTable}
+	if err := e.stagePrepareSchema(taskID, sc); err != nil {
+		return fmt.Errorf("建立连接失败: %w", err)
+	}
+	defer sc.reader.Close()
+	defer sc.writer.Close()
+
+	switch stageName {
+	case StageVerifyRowcount:
+		return e.runStage(taskID, unit, stageName, func() error { return e.verifyRowcount(sc) })
+	case StageVerifyChecksum:
+		return e.runStage(taskID, unit, stageName, func() error { return e.verifyChecksum(sc) })
+	case StageBuildIndexes:
+		indexes, err := sc.writer.ListSecondaryIndexes()
+		if err != nil {
+			return fmt.Errorf("查询索引失败: %w", err)
+		}
+		return e.runStage(taskID, unit, stageName, func() error {
+			for _, idx := range indexes {
+				if err := sc.writer.CreateIndex(idx); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	default:
+		return fmt.Errorf("阶段 %s 不支持单独重跑", stageName)
+	}
+}
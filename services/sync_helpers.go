@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -9,6 +10,11 @@ import (
 	"datatrace/models"
 )
 
+// shouldFlushCheckpoint 判断当前批次是否应该把续传断点落库；interval<=1表示未配置或显式要求每批次都落库
+func shouldFlushCheckpoint(batchNum, interval int) bool {
+	return interval <= 1 || batchNum%interval == 0
+}
+
 // safePercent 安全计算百分比，避免除零
 func safePercent(processed, total int64) float64 {
 	if total <= 0 {
@@ -64,6 +70,267 @@ func (e *SyncEngine) handleTableExistsStrategy(writer *MySQLWriter, sourceDB *sq
 	}
 }
 
+// writeModeFromConfig 解析任务配置中的写入模式，留空时默认普通INSERT
+func writeModeFromConfig(config *TaskConfig) WriteMode {
+	switch config.SyncConfig.WriteMode {
+	case string(WriteModeInsertIgnore), string(WriteModeUpsert), string(WriteModeReplace):
+		return WriteMode(config.SyncConfig.WriteMode)
+	default:
+		return WriteModeInsert
+	}
+}
+
+// resolveIncrementalColumn 确定全量同步断点续传使用的水位线列：TaskUnitConfig中显式配置优先
+// （incremental_mode="none"时禁用续传），否则退回reader自动探测到的单列主键
+func (e *SyncEngine) resolveIncrementalColumn(unit *models.TaskUnitRuntime, reader *MySQLReader) string {
+	var cfg models.TaskUnitConfig
+	err := database.DB.Where("task_id = ? AND unit_name = ?", unit.TaskID, unit.UnitName).First(&cfg).Error
+	if err == nil {
+		if cfg.IncrementalMode == "none" {
+			return ""
+		}
+		if cfg.IncrementalColumn != "" {
+			return cfg.IncrementalColumn
+		}
+	}
+	column, _ := reader.CursorWatermark()
+	return column
+}
+
+// runFullLoad 全量批量读取和写入（原 SyncTable 的批量读写逻辑）。当单元存在续传断点
+// （LastProcessedBatch/LastWatermark，来自上一次PauseTask/失败前由CheckpointStore.Flush落库的断点）时，
+// 改用基于续传列的WHERE col > ?游标读取，跳过已经同步过的行，而不是从头重跑
+func (e *SyncEngine) runFullLoad(ctx context.Context, taskID string, unit *models.TaskUnitRuntime, config *TaskConfig, reader *MySQLReader, writer *MySQLWriter) error {
+	mode := writeModeFromConfig(config)
+	incrementalColumn := e.resolveIncrementalColumn(unit, reader)
+	resuming := unit.LastProcessedBatch != nil && unit.LastWatermark != "" && incrementalColumn != ""
+
+	batchNum := 0
+	if unit.LastProcessedBatch != nil {
+		batchNum = *unit.LastProcessedBatch
+	}
+	if resuming {
+		e.logService.Info(taskID, fmt.Sprintf("表 %s 从断点续传: 批次 %d 之后，%s > %s",
+			unit.UnitName, batchNum, incrementalColumn, unit.LastWatermark))
+	}
+
+	checkpoint := NewCheckpointStore()
+	for {
+		select {
+		case <-ctx.Done():
+			return e.pauseUnit(unit, "任务被暂停")
+		default:
+		}
+
+		var records []map[string]interface{}
+		var err error
+		if resuming {
+			records, err = reader.ReadIncrementalBatch(incrementalColumn, unit.LastWatermark)
+		} else {
+			if !reader.HasMore() {
+				break
+			}
+			records, err = reader.ReadBatch()
+		}
+		if err != nil {
+			return e.failUnit(unit, fmt.Sprintf("读取数据失败: %v", err))
+		}
+
+		if len(records) == 0 {
+			break
+		}
+
+		batchNum++
+
+		// 写入批次：按分片提交事务，单片失败时逐行重试，单行冲突不再拖累整批，失败行单独上报
+		failures, err := writer.WriteBatchWithMode(records, mode, config.SyncConfig.BatchSize)
+		if err != nil {
+			return e.failUnit(unit, fmt.Sprintf("写入数据失败: %v", err))
+		}
+
+		if len(failures) > 0 {
+			if config.SyncConfig.ErrorStrategy == "pause" {
+				return e.failUnit(unit, fmt.Sprintf("批次 %d 存在 %d 行写入失败，首个错误(第%d行): %v",
+					batchNum, len(failures), failures[0].Index, failures[0].Err))
+			}
+			for _, rowErr := range failures {
+				e.logService.Error(taskID, fmt.Sprintf("批次 %d 第 %d 行写入失败(跳过): %v", batchNum, rowErr.Index, rowErr.Err))
+			}
+		}
+
+		watermark := ""
+		if incrementalColumn != "" {
+			watermark = fmt.Sprintf("%v", records[len(records)-1][incrementalColumn])
+		}
+		checkpoint.Advance(unit, batchNum, int64(len(records)-len(failures)), watermark)
+		if shouldFlushCheckpoint(batchNum, config.SyncConfig.CheckpointInterval) {
+			if err := checkpoint.Flush(unit); err != nil {
+				e.logService.Warning(taskID, fmt.Sprintf("保存续传断点失败: %v", err))
+			}
+		}
+
+		e.recordBatchProgress(taskID, unit, batchNum, int64(len(records)-len(failures)))
+	}
+
+	return nil
+}
+
+// runIncrementalLoad 按水位线增量读取和写入（upsert 语义）
+func (e *SyncEngine) runIncrementalLoad(ctx context.Context, taskID string, unit *models.TaskUnitRuntime, config *TaskConfig, reader *MySQLReader, writer *MySQLWriter, sourceDB string) error {
+	watermarkColumn, watermarkType := e.resolveWatermarkColumn(unit, config, reader, sourceDB)
+	if watermarkColumn == "" {
+		return e.failUnit(unit, "增量同步失败: 未找到可用的水位线列（需要显式配置、updated_at/update_time 或单列主键）")
+	}
+
+	// 水位线列确定后立即持久化，供断点续传使用
+	if unit.WatermarkColumn != watermarkColumn || unit.WatermarkType != watermarkType {
+		unit.WatermarkColumn = watermarkColumn
+		unit.WatermarkType = watermarkType
+		database.DB.Model(unit).Updates(map[string]interface{}{
+			"watermark_column": watermarkColumn,
+			"watermark_type":   watermarkType,
+		})
+	}
+
+	e.logService.Info(taskID, fmt.Sprintf("表 %s 增量同步，水位线列: %s (%s)", unit.UnitName, watermarkColumn, watermarkType))
+
+	checkpoint := NewCheckpointStore()
+	batchNum := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return e.pauseUnit(unit, "任务被暂停")
+		default:
+		}
+
+		records, err := reader.ReadIncrementalBatch(watermarkColumn, unit.WatermarkValue)
+		if err != nil {
+			return e.failUnit(unit, fmt.Sprintf("读取增量数据失败: %v", err))
+		}
+
+		if len(records) == 0 {
+			break
+		}
+
+		batchNum++
+
+		if err := writer.WriteBatchUpsert(records); err != nil {
+			if config.SyncConfig.ErrorStrategy == "pause" {
+				return e.failUnit(unit, fmt.Sprintf("写入增量数据失败: %v", err))
+			}
+			e.logService.Error(taskID, fmt.Sprintf("增量批次 %d 写入失败(跳过): %v", batchNum, err))
+			// 跳过该批次：内存中的水位线推进到该批次末尾，下一次读取从其后继续
+			unit.WatermarkValue = fmt.Sprintf("%v", records[len(records)-1][watermarkColumn])
+			continue
+		}
+
+		// 水位线游标每批次都要在内存中推进，下一次ReadIncrementalBatch才能从正确的位置继续读取；
+		// 是否落库则交给CheckpointInterval控制，减少高频场景下的写放大
+		lastVal := fmt.Sprintf("%v", records[len(records)-1][watermarkColumn])
+		unit.WatermarkValue = lastVal
+		checkpoint.Advance(unit, batchNum, int64(len(records)), lastVal)
+		if shouldFlushCheckpoint(batchNum, config.SyncConfig.CheckpointInterval) {
+			database.DB.Model(unit).Update("watermark_value", lastVal)
+			if err := checkpoint.Flush(unit); err != nil {
+				e.logService.Warning(taskID, fmt.Sprintf("保存续传断点失败: %v", err))
+			}
+		}
+
+		e.recordBatchProgress(taskID, unit, batchNum, int64(len(records)))
+
+		if len(records) < reader.batchSize {
+			break
+		}
+	}
+
+	return nil
+}
+
+// resolveWatermarkColumn 探测增量同步使用的水位线列：用户配置 > updated_at/update_time > 单列主键
+func (e *SyncEngine) resolveWatermarkColumn(unit *models.TaskUnitRuntime, config *TaskConfig, reader *MySQLReader, sourceDB string) (column string, colType string) {
+	// 已持久化过水位线列，断点续传时直接复用
+	if unit.WatermarkColumn != "" {
+		return unit.WatermarkColumn, unit.WatermarkType
+	}
+
+	// 用户在表配置中显式指定
+	for _, db := range config.SelectedDatabases {
+		for _, tbl := range db.Tables {
+			if fmt.Sprintf("%s.%s", db.Database, tbl.TargetTable) == unit.UnitName && tbl.WatermarkColumn != "" {
+				if reader.ColumnExists(sourceDB, tbl.WatermarkColumn) {
+					return tbl.WatermarkColumn, e.classifyColumnType(reader, sourceDB, tbl.WatermarkColumn)
+				}
+			}
+		}
+	}
+
+	// 常见的更新时间列
+	for _, candidate := range []string{"updated_at", "update_time"} {
+		if reader.ColumnExists(sourceDB, candidate) {
+			return candidate, e.classifyColumnType(reader, sourceDB, candidate)
+		}
+	}
+
+	// 回退到单列主键
+	if pk := reader.GetPrimaryKeyColumn(sourceDB); pk != "" {
+		return pk, e.classifyColumnType(reader, sourceDB, pk)
+	}
+
+	return "", ""
+}
+
+// classifyColumnType 将 information_schema 的 DATA_TYPE 归类为 int/time/string，用于展示和排查
+func (e *SyncEngine) classifyColumnType(reader *MySQLReader, sourceDB, column string) string {
+	dataType, err := reader.GetColumnType(sourceDB, column)
+	if err != nil {
+		return "string"
+	}
+	switch dataType {
+	case "int", "bigint", "smallint", "mediumint", "tinyint":
+		return "int"
+	case "datetime", "timestamp", "date":
+		return "time"
+	default:
+		return "string"
+	}
+}
+
+// recordBatchProgress 推送进度日志/SSE，供全量与增量同步共用；processed_records本身已由
+// CheckpointStore.Advance在调用前推进（是否已落库取决于CheckpointInterval），这里只负责展示
+func (e *SyncEngine) recordBatchProgress(taskID string, unit *models.TaskUnitRuntime, batchNum int, count int64) {
+	logMessage := fmt.Sprintf("表 %s 批次 %d: %d/%d (%.1f%%)",
+		unit.UnitName, batchNum, unit.ProcessedRecords, unit.TotalRecords,
+		safePercent(unit.ProcessedRecords, unit.TotalRecords))
+	fields := map[string]interface{}{
+		"unit":  unit.UnitName,
+		"batch": batchNum,
+		"rows":  count,
+	}
+	e.logService.AddLogFields(taskID, "info", logMessage, "sync", fields)
+	syncLog := TaskLog{
+		Time:     formatLogTime(time.Now()),
+		Level:    "info",
+		Message:  logMessage,
+		Category: "sync",
+		Fields:   fields,
+	}
+	e.sseService.BroadcastLogUpdate(taskID, []TaskLog{syncLog})
+	e.progressBroker.Publish(taskID)
+
+	e.logService.Info(taskID, logMessage)
+}
+
+// checksumTable 执行 CHECKSUM TABLE 并返回校验和字符串，供verify_checksum阶段比对源表与目标表
+func checksumTable(db *sql.DB, tableName string) (string, error) {
+	var name string
+	var checksum sql.NullInt64
+	query := fmt.Sprintf("CHECKSUM TABLE `%s`", tableName)
+	if err := db.QueryRow(query).Scan(&name, &checksum); err != nil {
+		return "", fmt.Errorf("计算校验和失败: %w", err)
+	}
+	return fmt.Sprintf("%d", checksum.Int64), nil
+}
+
 // failUnit 标记单元失败
 func (e *SyncEngine) failUnit(unit *models.TaskUnitRuntime, errMsg string) error {
 	unit.Status = "failed"
@@ -72,6 +339,25 @@ func (e *SyncEngine) failUnit(unit *models.TaskUnitRuntime, errMsg string) error
 	return fmt.Errorf(errMsg)
 }
 
+// completeUnit 标记单元完成并推送完成日志/进度，供空表快速完成与正常同步完成共用
+func (e *SyncEngine) completeUnit(taskID string, unit *models.TaskUnitRuntime) {
+	unit.Status = "completed"
+	unit.ProcessedRecords = unit.TotalRecords
+	database.DB.Save(unit)
+
+	completeMessage := fmt.Sprintf("表 %s 同步完成，共 %d 条记录", unit.UnitName, unit.ProcessedRecords)
+	e.logService.AddLog(taskID, "success", completeMessage, "complete")
+	e.sseService.BroadcastLogUpdate(taskID, []TaskLog{{
+		Time:     formatLogTime(time.Now()),
+		Level:    "success",
+		Message:  completeMessage,
+		Category: "complete",
+	}})
+	e.progressBroker.Publish(taskID)
+
+	e.logService.Info(taskID, completeMessage)
+}
+
 // pauseUnit 标记单元暂停
 func (e *SyncEngine) pauseUnit(unit *models.TaskUnitRuntime, msg string) error {
 	unit.Status = "paused"
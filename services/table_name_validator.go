@@ -35,3 +35,18 @@ func ValidateDatabaseName(name string) error {
 	}
 	return nil
 }
+
+// ValidateColumnName 校验列名是否合法，防止SQL注入；用于需要将用户输入的列名
+// 拼接进SQL语句（SELECT/ORDER BY/WHERE的字段名位置不能使用占位符）的场景
+func ValidateColumnName(name string) error {
+	if name == "" {
+		return fmt.Errorf("列名不能为空")
+	}
+	if len(name) > 64 {
+		return fmt.Errorf("列名长度不能超过64个字符")
+	}
+	if !validNameRegex.MatchString(name) {
+		return fmt.Errorf("列名包含非法字符: %s", name)
+	}
+	return nil
+}
@@ -12,11 +12,12 @@ import (
 	"github.com/google/uuid"
 )
 
-// TaskExecution 任务执行信息
+// TaskExecution 任务执行信息。任务单元的调度已经下放给跨任务共享的GlobalScheduler，
+// 这里只保留取消句柄和等待句柄：WaitGroup在每个单元Submit时Add(1)，
+// 单元执行完成（含被GlobalScheduler.CancelTask跳过）时Done，用于感知任务何时彻底退出
 type TaskExecution struct {
 	TaskID    string
 	Cancel    context.CancelFunc
-	TaskQueue chan *models.TaskUnitRuntime
 	WaitGroup *sync.WaitGroup
 }
 
@@ -61,22 +62,23 @@ func (s *TaskControlService) StartTask(taskID string) error {
 	database.DB.Model(&models.TaskUnitRuntime{}).Where("task_id = ?", taskID).Count(&totalCount)
 	database.DB.Model(&models.TaskUnitRuntime{}).Where("task_id = ? AND status = ?", taskID, "completed").Count(&completedCount)
 
-	// 如果所有任务单元都已完成，重置它们的状态为pending
+	// 如果所有任务单元都已完成，重置它们的状态为pending，并清除续传断点，从头开始同步
 	if totalCount > 0 && completedCount == totalCount {
 		database.DB.Model(&models.TaskUnitRuntime{}).
 			Where("task_id = ?", taskID).
 			Updates(map[string]interface{}{
-				"status":            "pending",
-				"processed_records": 0,
-				"total_records":     0,
+				"status":               "pending",
+				"processed_records":    0,
+				"total_records":        0,
+				"last_processed_batch": nil,
+				"last_watermark":       "",
 			})
 	}
 
 	// 4. 清理之前的日志，避免数据串台
-	logService := NewTaskLogService()
-	logService.mu.Lock()
-	delete(logService.logs, taskID)
-	logService.mu.Unlock()
+	if err := NewTaskLogService().ClearLogs(taskID); err != nil {
+		fmt.Printf("[WARNING] 清理任务日志失败: %v\n", err)
+	}
 
 	// 5. 解析配置获取线程数
 	var config TaskConfig
@@ -93,57 +95,83 @@ func (s *TaskControlService) StartTask(taskID string) error {
 		return fmt.Errorf("初始化任务单元失败: %w", err)
 	}
 
-	// 7. 查询所有待处理的任务单元
-	var units []models.TaskUnitRuntime
-	database.DB.Where("task_id = ? AND status IN ?", taskID, []string{"pending", "failed"}).Find(&units)
+	// 7. 集群模式下先选举出任务级别的owner节点：只有拿到taskID租约的节点才会调度该任务，
+	//    避免多个节点同时运行同一个task造成is_running split-brain
+	node := NewNodeRegistryService()
+	cluster := NewClusterService()
+	if err := cluster.AcquireLease(taskID, node.NodeID()); err != nil {
+		return fmt.Errorf("获取任务租约失败: %w", err)
+	}
+
+	// 8. 按节点亲和原子认领待处理的任务单元：
+	//    specify_ip 为空或"any"时任意节点可认领，否则只有IP匹配的节点可以认领
+	units, err := s.claimUnits(taskID, node.NodeID(), node.IP())
+	if err != nil {
+		return fmt.Errorf("认领任务单元失败: %w", err)
+	}
 
 	if len(units) == 0 {
 		return fmt.Errorf("没有待处理的任务单元")
 	}
 
-	// 6. 更新任务为运行状态
+	// 9. 更新任务为运行状态
 	task.IsRunning = true
 	if err := database.DB.Save(&task).Error; err != nil {
 		return fmt.Errorf("更新任务状态失败: %w", err)
 	}
 
-	// 7. 创建任务队列和context
+	// 10. 创建context
 	ctx, cancel := context.WithCancel(context.Background())
-	taskQueue := make(chan *models.TaskUnitRuntime, len(units))
-
 	execution := &TaskExecution{
 		TaskID:    taskID,
 		Cancel:    cancel,
-		TaskQueue: taskQueue,
 		WaitGroup: &sync.WaitGroup{},
 	}
 	s.executions.Store(taskID, execution)
 
-	// 8. 将所有任务单元放入队列
+	// 11. 把所有任务单元提交给跨任务共享的GlobalScheduler：单元的Priority非0时覆盖任务的Priority，
+	//    用于把同一任务里的热点表提到队列前面；threadCount作为本任务自身的并发上限，
+	//    与调度器按运行中任务数算出的公平份额取较小值，既限制单任务独占worker，也保留原有的"线程数"语义
+	scheduler := NewGlobalScheduler()
 	for i := range units {
-		taskQueue <- &units[i]
-	}
-	close(taskQueue) // 关闭队列，表示没有更多任务
-
-	// 9. 启动Worker Pool
-	engine := NewSyncEngine()
-	for i := 0; i < threadCount; i++ {
+		priority := task.Priority
+		if units[i].Priority != 0 {
+			priority = units[i].Priority
+		}
 		execution.WaitGroup.Add(1)
-		go func(workerID int) {
-			defer execution.WaitGroup.Done()
-			engine.Worker(ctx, taskID, taskQueue, workerID)
-		}(i)
+		scheduler.Submit(taskID, ctx, &units[i], priority, task.EndTime, threadCount, execution.WaitGroup.Done)
 	}
 
-	// 10. 启动监控goroutine，等待所有Worker完成
+	// 12. 启动租约续约协程，ctx取消（Pause/Stop）或任务完成后自动停止
+	go func() {
+		ticker := time.NewTicker(leaseRenewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := cluster.RenewLease(taskID, node.NodeID()); err != nil {
+					fmt.Printf("[WARNING] 续约任务 %s 的租约失败: %v\n", taskID, err)
+				}
+			}
+		}
+	}()
+
+	// 13. 启动监控goroutine，等待本任务提交给GlobalScheduler的所有单元处理完成
 	go func() {
 		execution.WaitGroup.Wait()
 		s.executions.Delete(taskID)
 
-		// 所有Worker完成，更新is_running为false
+		// 所有单元完成，更新is_running为false
 		database.DB.Model(&models.SyncTask{}).
 			Where("id = ?", taskID).
 			Update("is_running", false)
+
+		// 任务自然结束，主动释放租约，无需等待TTL过期其他节点才能再次启动
+		if err := cluster.ReleaseLease(taskID, node.NodeID()); err != nil {
+			fmt.Printf("[WARNING] 释放任务 %s 的租约失败: %v\n", taskID, err)
+		}
 	}()
 
 	return nil
@@ -162,20 +190,27 @@ func (s *TaskControlService) PauseTask(taskID string) error {
 		return fmt.Errorf("任务未在运行中")
 	}
 
-	// 3. 发送取消信号给所有Worker
+	// 3. 集群模式下任务可能由其他节点持有租约并实际在那里调度，本节点没有对应的
+	//    TaskExecution；这种情况下把Pause转发给真正的owner，而不是在本节点空操作
+	if handled, err := NewClusterService().ForwardIfRemoteOwner(taskID, "pause"); handled {
+		return err
+	}
+
+	// 4. 发送取消信号，并从GlobalScheduler中撤回本任务尚未被取出的单元，避免暂停期间继续被调度
 	if exec, ok := s.executions.Load(taskID); ok {
 		execution := exec.(*TaskExecution)
-		execution.Cancel()         // 发送取消信号
-		execution.WaitGroup.Wait() // 等待所有Worker退出
+		execution.Cancel()                      // 发送取消信号
+		NewGlobalScheduler().CancelTask(taskID) // 撤回排队中的单元
+		execution.WaitGroup.Wait()              // 等待所有已提交的单元（运行中/被撤回）全部结束
 	}
 
-	// 4. 更新任务状态
+	// 5. 更新任务状态
 	task.IsRunning = false
 	if err := database.DB.Save(&task).Error; err != nil {
 		return fmt.Errorf("更新任务状态失败: %w", err)
 	}
 
-	// 5. 更新运行中的任务单元状态为paused
+	// 6. 更新运行中的任务单元状态为paused
 	database.DB.Model(&models.TaskUnitRuntime{}).
 		Where("task_id = ? AND status = ?", taskID, "running").
 		Update("status", "paused")
@@ -191,22 +226,30 @@ func (s *TaskControlService) StopTask(taskID string) error {
 		return fmt.Errorf("任务不存在")
 	}
 
-	// 2. 发送取消信号给所有Worker（如果任务正在运行）
+	// 2. 集群模式下任务可能由其他节点持有租约并实际在那里调度，转发Stop给真正的owner
+	if task.IsRunning {
+		if handled, err := NewClusterService().ForwardIfRemoteOwner(taskID, "stop"); handled {
+			return err
+		}
+	}
+
+	// 3. 发送取消信号，并从GlobalScheduler中撤回本任务尚未被取出的单元（如果任务正在运行）
 	if task.IsRunning {
 		if exec, ok := s.executions.Load(taskID); ok {
 			execution := exec.(*TaskExecution)
-			execution.Cancel()         // 发送取消信号
-			execution.WaitGroup.Wait() // 等待所有Worker退出
+			execution.Cancel()                      // 发送取消信号
+			NewGlobalScheduler().CancelTask(taskID) // 撤回排队中的单元
+			execution.WaitGroup.Wait()              // 等待所有已提交的单元（运行中/被撤回）全部结束
 		}
 	}
 
-	// 3. 更新任务状态
+	// 4. 更新任务状态
 	task.IsRunning = false
 	if err := database.DB.Save(&task).Error; err != nil {
 		return fmt.Errorf("更新任务状态失败: %w", err)
 	}
 
-	// 4. 清除任务单元的状态，而不是删除它们
+	// 5. 清除任务单元的状态，而不是删除它们
 	database.DB.Model(&models.TaskUnitRuntime{}).
 		Where("task_id = ?", taskID).
 		Updates(map[string]interface{}{
@@ -214,12 +257,105 @@ func (s *TaskControlService) StopTask(taskID string) error {
 			"processed_records": 0,
 			"total_records":     0,
 			"started_at":        nil,
+			"node_id":           "",
 			"updated_at":        time.Now(),
 		})
 
 	return nil
 }
 
+// Reprioritize 调整任务的调度优先级/截止时间
+// 如果任务正在运行，会就地重建其在GlobalScheduler堆中尚未被取出的单元（不中断已经在运行的单元），否则只更新任务记录
+func (s *TaskControlService) Reprioritize(taskID string, priority int, endTime *time.Time) error {
+	var task models.SyncTask
+	if err := database.DB.First(&task, "id = ?", taskID).Error; err != nil {
+		return fmt.Errorf("任务不存在")
+	}
+
+	task.Priority = priority
+	task.EndTime = endTime
+	if err := database.DB.Save(&task).Error; err != nil {
+		return fmt.Errorf("更新任务优先级失败: %w", err)
+	}
+
+	if _, ok := s.executions.Load(taskID); ok {
+		NewGlobalScheduler().Rebuild(taskID, priority, endTime)
+	}
+
+	return nil
+}
+
+// ReprioritizeUnit 单独调整某个任务单元的调度优先级，不影响同任务内的其它单元。
+// 任务正在运行且该单元仍在GlobalScheduler的堆中等待调度时立即生效；已经被worker取出或任务未运行时
+// 只更新持久化的Priority，下次入队（重新启动任务）时生效
+func (s *TaskControlService) ReprioritizeUnit(taskID, unitName string, priority int) error {
+	var config models.TaskUnitConfig
+	if err := database.DB.Where("task_id = ? AND unit_name = ?", taskID, unitName).First(&config).Error; err != nil {
+		return fmt.Errorf("任务单元不存在")
+	}
+
+	if err := database.DB.Model(&models.TaskUnitConfig{}).
+		Where("task_id = ? AND unit_name = ?", taskID, unitName).
+		Update("priority", priority).Error; err != nil {
+		return fmt.Errorf("更新单元优先级失败: %w", err)
+	}
+	database.DB.Model(&models.TaskUnitRuntime{}).
+		Where("task_id = ? AND unit_name = ?", taskID, unitName).
+		Update("priority", priority)
+
+	if _, ok := s.executions.Load(taskID); ok {
+		NewGlobalScheduler().Reprioritize(taskID, unitName, priority)
+	}
+
+	return nil
+}
+
+// ResetCheckpoint 清除指定任务单元的续传断点，供操作员手动重跑整张表；任务运行中不允许重置，
+// 避免与正在写入的Worker竞争同一条记录
+func (s *TaskControlService) ResetCheckpoint(taskID, unitName string) error {
+	var task models.SyncTask
+	if err := database.DB.First(&task, "id = ?", taskID).Error; err != nil {
+		return fmt.Errorf("任务不存在")
+	}
+	if task.IsRunning {
+		return fmt.Errorf("任务正在运行中，无法重置断点")
+	}
+
+	var unit models.TaskUnitRuntime
+	if err := database.DB.Where("task_id = ? AND unit_name = ?", taskID, unitName).First(&unit).Error; err != nil {
+		return fmt.Errorf("任务单元不存在")
+	}
+
+	if err := NewCheckpointStore().Reset(&unit); err != nil {
+		return fmt.Errorf("重置断点失败: %w", err)
+	}
+
+	return database.DB.Model(&unit).Update("status", "pending").Error
+}
+
+// claimUnits 原子地将task的待处理单元认领给指定节点，返回认领成功的单元
+// 使用 MySQL 的派生表写法规避"UPDATE同一张表不能在子查询中引用自身"的限制
+func (s *TaskControlService) claimUnits(taskID, nodeID, nodeIP string) ([]models.TaskUnitRuntime, error) {
+	result := database.DB.Exec(`
+		UPDATE task_unit_runtimes SET node_id = ? WHERE id IN (
+			SELECT id FROM (
+				SELECT id FROM task_unit_runtimes
+				WHERE task_id = ? AND status IN ('pending', 'failed')
+				  AND (specify_ip = '' OR specify_ip = 'any' OR specify_ip = ?)
+			) claimable
+		)`, nodeID, taskID, nodeIP)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	var units []models.TaskUnitRuntime
+	if err := database.DB.Where("task_id = ? AND node_id = ? AND status IN ?",
+		taskID, nodeID, []string{"pending", "failed"}).Find(&units).Error; err != nil {
+		return nil, err
+	}
+	return units, nil
+}
+
 // initTaskUnits 初始化任务单元运行记录
 func (s *TaskControlService) initTaskUnits(taskID string) error {
 	// 检查是否已有运行记录
@@ -243,6 +379,10 @@ func (s *TaskControlService) initTaskUnits(taskID string) error {
 		return fmt.Errorf("任务没有配置单元")
 	}
 
+	// 任务单元继承任务的节点亲和配置
+	var task models.SyncTask
+	database.DB.Select("specify_ip").First(&task, "id = ?", taskID)
+
 	// 创建运行记录（pending状态不设置StartedAt，等实际开始运行时再设置）
 	now := time.Now()
 	var runtimes []models.TaskUnitRuntime
@@ -256,6 +396,8 @@ func (s *TaskControlService) initTaskUnits(taskID string) error {
 			ProcessedRecords: 0,
 			StartedAt:        nil,
 			UpdatedAt:        now,
+			SpecifyIP:        task.SpecifyIP,
+			Priority:         config.Priority,
 		}
 		runtimes = append(runtimes, runtime)
 	}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// EventType 事件总线上的事件类型
+type EventType string
+
+const (
+	EventProgress EventType = "progress" // 任务整体进度变化
+	EventLog      EventType = "log"      // 新增日志
+	EventStage    EventType = "stage"    // 任务单元阶段流转（prepare_schema/copy_data/...）
+)
+
+const (
+	eventRingBufferSize  = 200 // 每个任务保留的历史事件数量，用于断线重连重放
+	subscriberBufferSize = 32  // 每个订阅者的缓冲区大小
+)
+
+// TaskEvent 事件总线上流转的一条事件，ID 为任务内单调递增序号，对应SSE的Last-Event-ID
+type TaskEvent struct {
+	ID   int64       `json:"id"`
+	Type EventType   `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// subscriber 一个SSE客户端的订阅句柄
+type subscriber struct {
+	ch      chan TaskEvent
+	dropped int64 // 缓冲区满时丢弃的事件数，原子操作
+}
+
+// taskTopic 单个任务的事件主题：环形缓冲区 + 当前订阅者集合
+type taskTopic struct {
+	mu      sync.Mutex
+	nextSeq int64
+	ring    []TaskEvent
+	subs    map[*subscriber]bool
+}
+
+// TaskEventBus 进程内事件总线，取代原先"每个客户端每2秒轮询一次DB"的推送方式
+// SyncEngine 在状态变化时主动 Publish，SSE 客户端通过 Subscribe 接收并可凭 Last-Event-ID 断线重放
+type TaskEventBus struct {
+	mu     sync.Mutex
+	topics map[string]*taskTopic
+}
+
+var (
+	taskEventBusInstance *TaskEventBus
+	taskEventBusOnce     sync.Once
+)
+
+// NewTaskEventBus 获取事件总线单例
+func NewTaskEventBus() *TaskEventBus {
+	taskEventBusOnce.Do(func() {
+		taskEventBusInstance = &TaskEventBus{topics: make(map[string]*taskTopic)}
+	})
+	return taskEventBusInstance
+}
+
+func (b *TaskEventBus) topic(taskID string) *taskTopic {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.topics[taskID]
+	if !ok {
+		t = &taskTopic{subs: make(map[*subscriber]bool)}
+		b.topics[taskID] = t
+	}
+	return t
+}
+
+// Publish 发布一条事件：追加到环形缓冲区，并尽量推送给所有当前订阅者
+// 订阅者缓冲区已满时丢弃该订阅者最旧的一条事件腾出空间（drop-oldest），并累加其丢弃计数
+func (b *TaskEventBus) Publish(taskID string, eventType EventType, data interface{}) TaskEvent {
+	t := b.topic(taskID)
+
+	t.mu.Lock()
+	t.nextSeq++
+	event := TaskEvent{ID: t.nextSeq, Type: eventType, Data: data}
+	t.ring = append(t.ring, event)
+	if len(t.ring) > eventRingBufferSize {
+		t.ring = t.ring[len(t.ring)-eventRingBufferSize:]
+	}
+	subs := make([]*subscriber, 0, len(t.subs))
+	for s := range t.subs {
+		subs = append(subs, s)
+	}
+	t.mu.Unlock()
+
+	for _, s := range subs {
+		select {
+		case s.ch <- event:
+		default:
+			select {
+			case <-s.ch:
+			default:
+			}
+			select {
+			case s.ch <- event:
+			default:
+			}
+			atomic.AddInt64(&s.dropped, 1)
+		}
+	}
+
+	return event
+}
+
+// Subscribe 订阅某个任务的事件。lastEventID > 0 时返回环形缓冲区中该序号之后的历史事件用于重放
+func (b *TaskEventBus) Subscribe(taskID string, lastEventID int64) (*subscriber, []TaskEvent) {
+	t := b.topic(taskID)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var backlog []TaskEvent
+	if lastEventID > 0 {
+		for _, e := range t.ring {
+			if e.ID > lastEventID {
+				backlog = append(backlog, e)
+			}
+		}
+	}
+
+	s := &subscriber{ch: make(chan TaskEvent, subscriberBufferSize)}
+	t.subs[s] = true
+	return s, backlog
+}
+
+// Unsubscribe 取消订阅
+func (b *TaskEventBus) Unsubscribe(taskID string, s *subscriber) {
+	t := b.topic(taskID)
+	t.mu.Lock()
+	delete(t.subs, s)
+	t.mu.Unlock()
+}
+
+// popDropped 取出并清零该订阅者当前的丢弃计数
+func (s *subscriber) popDropped() int64 {
+	return atomic.SwapInt64(&s.dropped, 0)
+}
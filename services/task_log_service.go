@@ -4,79 +4,118 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"datatrace/config"
 )
 
-// TaskLogService 任务日志服务
-type TaskLogService struct {
-	logs map[string][]TaskLog // taskID -> logs
-	mu   sync.RWMutex
+// TaskLog 任务日志（对外DTO，SSE与HTTP接口共用）
+type TaskLog struct {
+	Time     string                 `json:"time"`             // RFC3339Nano格式时间
+	Level    string                 `json:"level"`            // 日志级别：info/success/warning/error
+	Message  string                 `json:"message"`          // 日志消息
+	Category string                 `json:"category"`         // 日志分类：all/create/sync/complete
+	Fields   map[string]interface{} `json:"fields,omitempty"` // 结构化上下文：unit/batch/rows/duration_ms等
 }
 
-// NewTaskLogService 创建任务日志服务
-func NewTaskLogService() *TaskLogService {
-	return &TaskLogService{
-		logs: make(map[string][]TaskLog),
-	}
+// LogQuery 日志检索条件，零值字段表示不过滤
+type LogQuery struct {
+	Level    string
+	Category string
+	Since    time.Time
+	Until    time.Time
+	Search   string
+	Limit    int
+	Offset   int
 }
 
-// TaskLog 任务日志
-type TaskLog struct {
-	Time     string `json:"time"`     // 时间
-	Level    string `json:"level"`    // 日志级别：info/success/warning/error
-	Message  string `json:"message"`  // 日志消息
-	Category string `json:"category"` // 日志分类：all/create/sync/complete
+// LogStore 任务日志存储后端：既支持内存环形缓冲区，也支持GORM持久化表，由task_log.store_backend配置项选择
+type LogStore interface {
+	// Append 追加一条日志
+	Append(taskID string, log TaskLog) error
+	// Recent 按时间正序返回最近的limit条日志
+	Recent(taskID string, limit int) ([]TaskLog, error)
+	// Search 按条件分页检索日志，结果按时间正序排列
+	Search(taskID string, q LogQuery) ([]TaskLog, error)
+	// Clear 清空某个任务的全部日志
+	Clear(taskID string) error
 }
 
-// GetTaskLogs 获取任务日志
-func (s *TaskLogService) GetTaskLogs(taskID string, limit int) ([]TaskLog, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// TaskLogService 任务日志服务，持久化策略由LogStore实现决定
+type TaskLogService struct {
+	store LogStore
+}
 
-	logs, exists := s.logs[taskID]
-	if !exists {
-		return []TaskLog{}, nil
-	}
+var (
+	taskLogInstance *TaskLogService
+	taskLogOnce     sync.Once
+)
+
+// NewTaskLogService 获取任务日志服务单例
+func NewTaskLogService() *TaskLogService {
+	taskLogOnce.Do(func() {
+		taskLogInstance = &TaskLogService{store: newLogStore()}
+	})
+	return taskLogInstance
+}
 
-	// 限制日志数量
-	if limit > 0 && len(logs) > limit {
-		logs = logs[len(logs)-limit:]
+// newLogStore 根据配置创建日志存储后端，未加载配置或未指定时默认使用内存环形缓冲区
+func newLogStore() LogStore {
+	if config.GlobalConfig != nil && config.GlobalConfig.TaskLog.StoreBackend == "gorm" {
+		return newGormLogStore()
 	}
+	return newMemoryLogStore()
+}
+
+// GetTaskLogs 按任务ID、级别、分类、时间范围、关键字分页检索任务日志，结果按时间正序排列
+func (s *TaskLogService) GetTaskLogs(taskID, level, category string, since, until time.Time, search string, limit, offset int) ([]TaskLog, error) {
+	return s.store.Search(taskID, LogQuery{
+		Level:    level,
+		Category: category,
+		Since:    since,
+		Until:    until,
+		Search:   search,
+		Limit:    limit,
+		Offset:   offset,
+	})
+}
+
+// RecentLogs 获取某个任务最近的limit条日志，供SSE断线重连首次快照等场景使用
+func (s *TaskLogService) RecentLogs(taskID string, limit int) ([]TaskLog, error) {
+	return s.store.Recent(taskID, limit)
+}
 
-	return logs, nil
+// ClearLogs 清空某个任务的全部日志，任务重新启动前调用以避免新旧日志串台
+func (s *TaskLogService) ClearLogs(taskID string) error {
+	return s.store.Clear(taskID)
 }
 
 // AddLog 添加日志
 func (s *TaskLogService) AddLog(taskID string, level string, message string, category string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if s.logs[taskID] == nil {
-		s.logs[taskID] = []TaskLog{}
-	}
+	s.AddLogFields(taskID, level, message, category, nil)
+}
 
+// AddLogFields 添加带结构化上下文的日志（如unit/batch/rows/duration_ms）
+func (s *TaskLogService) AddLogFields(taskID string, level string, message string, category string, fields map[string]interface{}) {
 	log := TaskLog{
 		Time:     formatLogTime(time.Now()),
 		Level:    level,
 		Message:  message,
 		Category: category,
+		Fields:   fields,
 	}
-
-	s.logs[taskID] = append(s.logs[taskID], log)
-
-	// 限制日志数量（最多1000条）
-	if len(s.logs[taskID]) > 1000 {
-		s.logs[taskID] = s.logs[taskID][len(s.logs[taskID])-1000:]
+	if err := s.store.Append(taskID, log); err != nil {
+		fmt.Printf("[ERROR] 写入任务日志失败: %v\n", err)
 	}
 }
 
 // formatLogTime 格式化日志时间
 func formatLogTime(t time.Time) string {
-	return t.Format("15:04:05")
+	return t.Format(time.RFC3339Nano)
 }
 
 // Info 记录信息日志
 func (s *TaskLogService) Info(taskID string, message string) {
-	// 添加到内存日志
+	// 添加到日志存储
 	s.AddLog(taskID, "info", message, "all")
 	// 打印到控制台
 	fmt.Printf("[INFO] [Task:%s] %s\n", taskID, message)
@@ -84,7 +123,7 @@ func (s *TaskLogService) Info(taskID string, message string) {
 
 // Error 记录错误日志
 func (s *TaskLogService) Error(taskID string, message string) {
-	// 添加到内存日志
+	// 添加到日志存储
 	s.AddLog(taskID, "error", message, "all")
 	// 打印到控制台
 	fmt.Printf("[ERROR] [Task:%s] %s\n", taskID, message)
@@ -92,7 +131,7 @@ func (s *TaskLogService) Error(taskID string, message string) {
 
 // Warning 记录警告日志
 func (s *TaskLogService) Warning(taskID string, message string) {
-	// 添加到内存日志
+	// 添加到日志存储
 	s.AddLog(taskID, "warning", message, "all")
 	// 打印到控制台
 	fmt.Printf("[WARNING] [Task:%s] %s\n", taskID, message)
@@ -100,7 +139,7 @@ func (s *TaskLogService) Warning(taskID string, message string) {
 
 // Success 记录成功日志
 func (s *TaskLogService) Success(taskID string, message string) {
-	// 添加到内存日志
+	// 添加到日志存储
 	s.AddLog(taskID, "success", message, "complete")
 	// 打印到控制台
 	fmt.Printf("[SUCCESS] [Task:%s] %s\n", taskID, message)
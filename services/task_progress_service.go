@@ -42,6 +42,7 @@ type TableUnit struct {
 	TotalRecords     int64   `json:"total_records"`     // 总记录数
 	ProcessedRecords int64   `json:"processed_records"` // 已处理记录数
 	Progress         float64 `json:"progress"`          // 进度百分比
+	NodeID           string  `json:"node_id,omitempty"` // 当前认领该单元的集群节点ID
 }
 
 // GetTaskProgress 获取任务进度
@@ -117,6 +118,7 @@ func (s *TaskProgressService) GetTaskProgress(taskID string) (*TaskProgress, err
 			TotalRecords:     runtime.TotalRecords,
 			ProcessedRecords: runtime.ProcessedRecords,
 			Progress:         unitProgress,
+			NodeID:           runtime.NodeID,
 		}
 
 		tableUnits = append(tableUnits, unit)
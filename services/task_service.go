@@ -5,6 +5,7 @@ import (
 	"datatrace/models"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -23,10 +24,12 @@ func NewTaskService() *TaskService {
 
 // CreateTaskRequest 创建任务请求
 type CreateTaskRequest struct {
-	Name       string `json:"name" binding:"required"`
-	SourceType string `json:"source_type" binding:"required"`
-	TargetType string `json:"target_type" binding:"required"`
-	Remark     string `json:"remark"`
+	Name       string     `json:"name" binding:"required"`
+	SourceType string     `json:"source_type" binding:"required"`
+	TargetType string     `json:"target_type" binding:"required"`
+	Remark     string     `json:"remark"`
+	Priority   int        `json:"priority"` // 调度优先级，数字越小优先级越高，默认5
+	EndTime    *time.Time `json:"end_time"` // 可选截止时间
 }
 
 // UpdateTaskConfigRequest 更新任务配置请求
@@ -35,6 +38,15 @@ type UpdateTaskConfigRequest struct {
 	TargetID          string              `json:"target_id" binding:"required"`
 	SelectedDatabases []DatabaseSelection `json:"selected_databases"`
 	SyncConfig        SyncConfigParams    `json:"sync_config"`
+	Priority          int                 `json:"priority"`
+	EndTime           *time.Time          `json:"end_time"`
+	SpecifyIP         string              `json:"specify_ip"` // 节点亲和，空或"any"表示不限制节点
+}
+
+// UpdateTaskPriorityRequest 调整运行中任务优先级的请求
+type UpdateTaskPriorityRequest struct {
+	Priority int        `json:"priority" binding:"required"`
+	EndTime  *time.Time `json:"end_time"`
 }
 
 // DatabaseSelection 数据库选择
@@ -47,18 +59,25 @@ type DatabaseSelection struct {
 
 // TableConfig 表配置
 type TableConfig struct {
-	SourceTable string `json:"source_table"`
-	TargetTable string `json:"target_table"`
-	IsModified  bool   `json:"is_modified"`
+	SourceTable       string `json:"source_table"`
+	TargetTable       string `json:"target_table"`
+	IsModified        bool   `json:"is_modified"`
+	WatermarkColumn   string `json:"watermark_column"`   // 增量同步水位线列，留空则自动探测
+	IncrementalColumn string `json:"incremental_column"` // 断点续传使用的水位线列，留空则全量同步自动探测单列主键
+	IncrementalMode   string `json:"incremental_mode"`   // pk/timestamp/none，留空等价于自动探测
+	Priority          int    `json:"priority"`           // 单元级调度优先级，0表示沿用任务的Priority
+	Weight            int    `json:"weight"`             // 预留给未来的加权公平调度，当前仅持久化
 }
 
 // SyncConfigParams 同步配置参数
 type SyncConfigParams struct {
 	BatchSize           int    `json:"batch_size"`
 	ThreadCount         int    `json:"thread_count"`
-	SyncMode            string `json:"sync_mode"` // full/incremental
+	SyncMode            string `json:"sync_mode"` // full/incremental/binlog
 	ErrorStrategy       string `json:"error_strategy"`
 	TableExistsStrategy string `json:"table_exists_strategy"`
+	WriteMode           string `json:"write_mode"`          // insert/insert_ignore/upsert/replace，全量同步写入冲突处理策略，留空默认insert
+	CheckpointInterval  int    `json:"checkpoint_interval"` // 每隔多少批次落库一次续传断点，<=1时每批次都落库；调大可减少高频写入task_unit_runtimes的开销，代价是崩溃/暂停后可能重放更多批次
 }
 
 // TaskConfig 任务配置（存储在config字段的JSON）
@@ -83,6 +102,12 @@ func (s *TaskService) Create(req *CreateTaskRequest) (*models.SyncTask, error) {
 		return nil, fmt.Errorf("任务名称已存在")
 	}
 
+	// 优先级默认5（中等），数字越小优先级越高
+	priority := req.Priority
+	if priority <= 0 {
+		priority = 5
+	}
+
 	// 创建任务（初始配置为空，数据源ID稍后配置时设置）
 	task := &models.SyncTask{
 		ID:         uuid.New().String(),
@@ -94,6 +119,8 @@ func (s *TaskService) Create(req *CreateTaskRequest) (*models.SyncTask, error) {
 		Config:     "{}",
 		Status:     "idle",
 		SyncMode:   "auto",
+		Priority:   priority,
+		EndTime:    req.EndTime,
 	}
 
 	if err := database.DB.Create(task).Error; err != nil {
@@ -158,6 +185,12 @@ func (s *TaskService) UpdateConfig(id string, req *UpdateTaskConfigRequest) (*mo
 		task.SyncMode = req.SyncConfig.SyncMode
 	}
 
+	if req.Priority > 0 {
+		task.Priority = req.Priority
+	}
+	task.EndTime = req.EndTime
+	task.SpecifyIP = req.SpecifyIP
+
 	if err := database.DB.Save(task).Error; err != nil {
 		return nil, fmt.Errorf("更新失败: %w", err)
 	}
@@ -219,10 +252,14 @@ func (s *TaskService) generateTaskUnits(task *models.SyncTask) error {
 			}
 
 			unit := models.TaskUnitConfig{
-				ID:       uuid.New().String(),
-				TaskID:   task.ID,
-				UnitName: fmt.Sprintf("%s.%s", targetDatabase, targetTable),
-				UnitType: "table",
+				ID:                uuid.New().String(),
+				TaskID:            task.ID,
+				UnitName:          fmt.Sprintf("%s.%s", targetDatabase, targetTable),
+				UnitType:          "table",
+				IncrementalColumn: tableConfig.IncrementalColumn,
+				IncrementalMode:   tableConfig.IncrementalMode,
+				Priority:          tableConfig.Priority,
+				Weight:            tableConfig.Weight,
 			}
 			units = append(units, unit)
 		}
@@ -1,26 +1,25 @@
 package services
 
 import (
-	"datatrace/database"
-	"datatrace/models"
 	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
 )
 
-// TaskSSEService SSE服务
+// TaskSSEService SSE服务，基于 TaskEventBus 做事件驱动推送，替代原先的2秒轮询
 type TaskSSEService struct {
+	bus             *TaskEventBus
 	progressService *TaskProgressService
 	logService      *TaskLogService
-	clients         map[string]map[chan SSEMessage]bool // taskID -> clients
-	mu              sync.RWMutex
 }
 
 // SSEMessage SSE消息
 type SSEMessage struct {
-	Event string      `json:"event"` // progress, log, error
-	Data  interface{} `json:"data"`
+	ID     int64       `json:"id,omitempty"`
+	TaskID string      `json:"task_id,omitempty"` // 仅/api/v1/events多路复用场景下设置，标识事件所属任务
+	Event  string      `json:"event"`             // progress, log, stage, overflow, heartbeat
+	Data   interface{} `json:"data"`
 }
 
 var (
@@ -32,166 +31,265 @@ var (
 func NewTaskSSEService() *TaskSSEService {
 	taskSSEOnce.Do(func() {
 		taskSSEInstance = &TaskSSEService{
+			bus:             NewTaskEventBus(),
 			progressService: NewTaskProgressService(),
 			logService:      NewTaskLogService(),
-			clients:         make(map[string]map[chan SSEMessage]bool),
 		}
 	})
 	return taskSSEInstance
 }
 
-// AddClient 添加客户端
-func (s *TaskSSEService) AddClient(taskID string, client chan SSEMessage) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// StreamTaskUpdates 订阅并推送任务更新到out。lastEventID>0时先重放缓冲区中该序号之后的历史事件，
+// 再切换到实时推送；done被关闭时退出。
+func (s *TaskSSEService) StreamTaskUpdates(taskID string, lastEventID int64, out chan<- SSEMessage, done <-chan struct{}) {
+	sub, backlog := s.bus.Subscribe(taskID, lastEventID)
+	defer s.bus.Unsubscribe(taskID, sub)
 
-	if s.clients[taskID] == nil {
-		s.clients[taskID] = make(map[chan SSEMessage]bool)
+	// 全新连接（没有带Last-Event-ID）时补发一次当前快照，避免客户端要等到下次状态变化才看到数据
+	if lastEventID == 0 {
+		s.publishSnapshot(taskID)
 	}
-	s.clients[taskID][client] = true
-}
-
-// RemoveClient 移除客户端
-func (s *TaskSSEService) RemoveClient(taskID string, client chan SSEMessage) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
 
-	if clients, ok := s.clients[taskID]; ok {
-		delete(clients, client)
-		if len(clients) == 0 {
-			delete(s.clients, taskID)
+	for _, e := range backlog {
+		select {
+		case out <- toSSEMessage(e):
+		case <-done:
+			return
 		}
 	}
-	close(client)
-}
-
-// StreamTaskUpdates 流式推送任务更新
-func (s *TaskSSEService) StreamTaskUpdates(taskID string, client chan SSEMessage, done <-chan struct{}) {
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
 
-	// 立即发送一次
-	s.sendUpdate(taskID, client)
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
 
 	for {
 		select {
 		case <-done:
 			return
-		case <-ticker.C:
-			// 检查任务是否正在运行
-			var task models.SyncTask
-			if err := database.DB.First(&task, "id = ?", taskID).Error; err == nil {
-				// 如果任务不在运行，停止推送
-				if !task.IsRunning {
+
+		case e := <-sub.ch:
+			if dropped := sub.popDropped(); dropped > 0 {
+				select {
+				case out <- SSEMessage{Event: "overflow", Data: fmt.Sprintf("%d条消息因客户端处理过慢被丢弃", dropped)}:
+				case <-done:
 					return
 				}
 			}
-			s.sendUpdate(taskID, client)
+			select {
+			case out <- toSSEMessage(e):
+			case <-done:
+				return
+			}
+
+		case <-heartbeat.C:
+			select {
+			case out <- SSEMessage{Event: "heartbeat", Data: time.Now().Unix()}:
+			case <-done:
+				return
+			}
 		}
 	}
 }
 
-// sendUpdate 发送更新
-func (s *TaskSSEService) sendUpdate(taskID string, client chan SSEMessage) {
-	// 获取进度
-	progress, err := s.progressService.GetTaskProgress(taskID)
-	if err == nil {
-		select {
-		case client <- SSEMessage{
-			Event: "progress",
-			Data:  progress,
-		}:
-		default:
-			// 客户端缓冲区满，跳过
-		}
+// StreamMultiTaskUpdates 在同一条连接上同时订阅多个任务的更新，供/api/v1/events多路复用端点使用；
+// 每条转发给out的消息都会带上TaskID以便客户端区分来源，多个任务共用同一个15秒心跳
+func (s *TaskSSEService) StreamMultiTaskUpdates(taskIDs []string, lastEventIDs map[string]int64, out chan<- SSEMessage, done <-chan struct{}) {
+	type subEntry struct {
+		taskID string
+		sub    *subscriber
 	}
+	subs := make([]subEntry, 0, len(taskIDs))
+	defer func() {
+		for _, e := range subs {
+			s.bus.Unsubscribe(e.taskID, e.sub)
+		}
+	}()
 
-	// 获取日志
-	logs, err := s.logService.GetTaskLogs(taskID, 50)
-	if err == nil {
-		select {
-		case client <- SSEMessage{
-			Event: "log",
-			Data:  logs,
-		}:
-		default:
-			// 客户端缓冲区满，跳过
+	merged := make(chan SSEMessage, subscriberBufferSize*len(taskIDs))
+
+	for _, taskID := range taskIDs {
+		lastID := lastEventIDs[taskID]
+		sub, backlog := s.bus.Subscribe(taskID, lastID)
+		subs = append(subs, subEntry{taskID: taskID, sub: sub})
+
+		if lastID == 0 {
+			s.publishSnapshot(taskID)
+		}
+		for _, e := range backlog {
+			msg := toSSEMessage(e)
+			msg.TaskID = taskID
+			select {
+			case out <- msg:
+			case <-done:
+				return
+			}
 		}
 	}
-}
 
-// BroadcastProgressUpdate 广播进度更新
-func (s *TaskSSEService) BroadcastProgressUpdate(taskID string) {
-	// 检查任务是否正在运行
-	var task models.SyncTask
-	if err := database.DB.First(&task, "id = ?", taskID).Error; err == nil {
-		// 如果任务不在运行，停止推送
-		if !task.IsRunning {
+	for _, e := range subs {
+		go func(taskID string, sub *subscriber) {
+			for {
+				select {
+				case <-done:
+					return
+				case ev := <-sub.ch:
+					if dropped := sub.popDropped(); dropped > 0 {
+						select {
+						case merged <- SSEMessage{Event: "overflow", Data: fmt.Sprintf("%d条消息因客户端处理过慢被丢弃", dropped), TaskID: taskID}:
+						case <-done:
+							return
+						}
+					}
+					msg := toSSEMessage(ev)
+					msg.TaskID = taskID
+					select {
+					case merged <- msg:
+					case <-done:
+						return
+					}
+				}
+			}
+		}(e.taskID, e.sub)
+	}
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-done:
 			return
+
+		case msg := <-merged:
+			select {
+			case out <- msg:
+			case <-done:
+				return
+			}
+
+		case <-heartbeat.C:
+			select {
+			case out <- SSEMessage{Event: "heartbeat", Data: time.Now().Unix()}:
+			case <-done:
+				return
+			}
 		}
 	}
+}
 
-	s.mu.RLock()
-	clients, ok := s.clients[taskID]
-	s.mu.RUnlock()
+// StreamTaskLogs 订阅指定任务的日志事件，按level/category在服务端过滤后推送到out，
+// 供Web端实时跟随运行中任务的日志而无需轮询；done被关闭时退出。
+func (s *TaskSSEService) StreamTaskLogs(taskID, level, category string, out chan<- SSEMessage, done <-chan struct{}) {
+	sub, _ := s.bus.Subscribe(taskID, 0)
+	defer s.bus.Unsubscribe(taskID, sub)
 
-	if !ok {
-		return
+	// 连接建立时补发一次最近日志快照（按过滤条件），避免客户端要等到下一条新日志才看到数据
+	if snapshot, err := s.logService.RecentLogs(taskID, 50); err == nil {
+		if filtered := filterTaskLogs(snapshot, level, category); len(filtered) > 0 {
+			select {
+			case out <- SSEMessage{Event: "log", Data: filtered}:
+			case <-done:
+				return
+			}
+		}
 	}
 
-	// 获取最新进度
-	progress, err := s.progressService.GetTaskProgress(taskID)
-	if err != nil {
-		return
-	}
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
 
-	// 向所有客户端发送更新
-	for client := range clients {
+	for {
 		select {
-		case client <- SSEMessage{
-			Event: "progress",
-			Data:  progress,
-		}:
-		default:
-			// 客户端缓冲区满，跳过
+		case <-done:
+			return
+
+		case e := <-sub.ch:
+			if e.Type != EventLog {
+				continue
+			}
+			logs, ok := e.Data.([]TaskLog)
+			if !ok {
+				continue
+			}
+			filtered := filterTaskLogs(logs, level, category)
+			if len(filtered) == 0 {
+				continue
+			}
+			select {
+			case out <- SSEMessage{ID: e.ID, Event: "log", Data: filtered}:
+			case <-done:
+				return
+			}
+
+		case <-heartbeat.C:
+			select {
+			case out <- SSEMessage{Event: "heartbeat", Data: time.Now().Unix()}:
+			case <-done:
+				return
+			}
 		}
 	}
 }
 
-// BroadcastLogUpdate 广播日志更新
-func (s *TaskSSEService) BroadcastLogUpdate(taskID string, logs []TaskLog) {
-	// 检查任务是否正在运行
-	var task models.SyncTask
-	if err := database.DB.First(&task, "id = ?", taskID).Error; err == nil {
-		// 如果任务不在运行，停止推送
-		if !task.IsRunning {
-			return
+// filterTaskLogs 按level/category过滤日志，空字符串或"all"表示不过滤
+func filterTaskLogs(logs []TaskLog, level, category string) []TaskLog {
+	if (level == "" || level == "all") && (category == "" || category == "all") {
+		return logs
+	}
+	filtered := make([]TaskLog, 0, len(logs))
+	for _, l := range logs {
+		if level != "" && level != "all" && l.Level != level {
+			continue
+		}
+		if category != "" && category != "all" && l.Category != category {
+			continue
 		}
+		filtered = append(filtered, l)
 	}
+	return filtered
+}
 
-	s.mu.RLock()
-	clients, ok := s.clients[taskID]
-	s.mu.RUnlock()
+// publishSnapshot 将当前进度和最近日志作为一次性事件发布，供新连接的客户端立即看到现状
+func (s *TaskSSEService) publishSnapshot(taskID string) {
+	if progress, err := s.progressService.GetTaskProgress(taskID); err == nil {
+		s.bus.Publish(taskID, EventProgress, progress)
+	}
+	if logs, err := s.logService.RecentLogs(taskID, 50); err == nil && len(logs) > 0 {
+		s.bus.Publish(taskID, EventLog, logs)
+	}
+}
 
-	if !ok {
+// toSSEMessage 将总线事件转换为对外的SSE消息
+func toSSEMessage(e TaskEvent) SSEMessage {
+	return SSEMessage{ID: e.ID, Event: string(e.Type), Data: e.Data}
+}
+
+// BroadcastProgressUpdate 广播进度更新：发布到事件总线，由总线按订阅者各自的节奏推送
+func (s *TaskSSEService) BroadcastProgressUpdate(taskID string) {
+	progress, err := s.progressService.GetTaskProgress(taskID)
+	if err != nil {
 		return
 	}
+	s.bus.Publish(taskID, EventProgress, progress)
+}
 
-	// 向所有客户端发送更新
-	for client := range clients {
-		select {
-		case client <- SSEMessage{
-			Event: "log",
-			Data:  logs,
-		}:
-		default:
-			// 客户端缓冲区满，跳过
-		}
-	}
+// BroadcastLogUpdate 广播日志更新：调用方（SyncEngine）已经持有最新日志，直接发布，无需再查询DB
+func (s *TaskSSEService) BroadcastLogUpdate(taskID string, logs []TaskLog) {
+	s.bus.Publish(taskID, EventLog, logs)
 }
 
-// FormatSSEMessage 格式化SSE消息
+// FormatSSEMessage 格式化SSE消息，带上id字段以支持浏览器原生的Last-Event-ID自动重连；
+// heartbeat以SSE注释行（以冒号开头）发送，浏览器不会将其作为事件派发，只用于保持连接/防止代理超时断开
 func FormatSSEMessage(msg SSEMessage) string {
+	if msg.Event == "heartbeat" {
+		return ":heartbeat\n\n"
+	}
+
 	data, _ := json.Marshal(msg.Data)
+	id := fmt.Sprintf("%d", msg.ID)
+	if msg.TaskID != "" {
+		id = fmt.Sprintf("%s:%d", msg.TaskID, msg.ID)
+	}
+	if msg.ID > 0 {
+		return fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", id, msg.Event, string(data))
+	}
 	return fmt.Sprintf("event: %s\ndata: %s\n\n", msg.Event, string(data))
 }
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"datatrace/database"
+	"datatrace/models"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// UserService 用户管理服务
+type UserService struct {
+	auth *AuthService
+}
+
+// NewUserService 创建用户管理服务
+func NewUserService() *UserService {
+	return &UserService{auth: NewAuthService()}
+}
+
+// CreateUserRequest 创建用户请求
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role" binding:"required"` // admin/operator/viewer
+}
+
+// Create 创建用户
+func (s *UserService) Create(req *CreateUserRequest) (*models.User, error) {
+	var count int64
+	database.DB.Model(&models.User{}).Where("username = ?", req.Username).Count(&count)
+	if count > 0 {
+		return nil, fmt.Errorf("用户名已存在")
+	}
+
+	hash, err := s.auth.HashPassword(req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("密码加密失败: %w", err)
+	}
+
+	user := &models.User{
+		ID:           uuid.New().String(),
+		Username:     req.Username,
+		PasswordHash: hash,
+		Role:         req.Role,
+	}
+	if err := database.DB.Create(user).Error; err != nil {
+		return nil, fmt.Errorf("创建用户失败: %w", err)
+	}
+	return user, nil
+}
+
+// List 获取用户列表
+func (s *UserService) List() ([]models.User, error) {
+	var users []models.User
+	if err := database.DB.Order("created_at desc").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetByID 获取用户详情
+func (s *UserService) GetByID(id string) (*models.User, error) {
+	var user models.User
+	if err := database.DB.First(&user, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Delete 删除用户
+func (s *UserService) Delete(id string) error {
+	return database.DB.Delete(&models.User{}, "id = ?", id).Error
+}
+
+// Count 统计用户总数
+func (s *UserService) Count() (int64, error) {
+	var count int64
+	err := database.DB.Model(&models.User{}).Count(&count).Error
+	return count, err
+}
+
+// BootstrapDefaultAdmin 当users表为空时（首次启动）创建默认管理员账号，返回一次性明文密码；
+// 表非空时返回空字符串，调用方据此判断是否需要打印提示
+func (s *UserService) BootstrapDefaultAdmin() (string, error) {
+	count, err := s.Count()
+	if err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return "", nil
+	}
+
+	password := uuid.New().String()[:12]
+	if _, err := s.Create(&CreateUserRequest{
+		Username: "admin",
+		Password: password,
+		Role:     "admin",
+	}); err != nil {
+		return "", err
+	}
+	return password, nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Writer 数据写入器的统一接口，不同数据源类型各自实现批量写入和目标表的生命周期管理
+type Writer interface {
+	WriteBatch(records []map[string]interface{}) error
+	CreateTableLike(columns []ColumnInfo) error
+	TruncateTable() error
+	DropTable() error
+	Close() error
+}
+
+// WriterConfig 创建Writer所需的连接与目标表信息
+type WriterConfig struct {
+	Host     string // SQLite数据源复用该字段为数据库文件路径
+	Port     int
+	Username string
+	Password string
+	Database string
+	Table    string
+	UseSSL   bool // Elasticsearch专用：是否使用HTTPS协议
+}
+
+// NewWriter 按数据源类型创建对应的写入器
+func NewWriter(dsType string, cfg WriterConfig) (Writer, error) {
+	switch dsType {
+	case "mysql":
+		mw, err := NewMySQLWriter(cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.Database, cfg.Table)
+		if err != nil {
+			return nil, err
+		}
+		return &mysqlGenericWriter{mw}, nil
+	case "postgresql":
+		return NewPostgreSQLWriter(cfg)
+	case "sqlite":
+		return NewSQLiteWriter(cfg)
+	case "sqlserver":
+		return NewSQLServerWriter(cfg)
+	case "mongodb":
+		return NewMongoDBWriter(cfg)
+	case "elasticsearch":
+		return NewElasticsearchWriter(cfg)
+	default:
+		return nil, fmt.Errorf("数据源类型 %s 不支持写入", dsType)
+	}
+}
+
+// mysqlGenericWriter 适配MySQLWriter到通用Writer接口；MySQL到MySQL的既有CDC流水线
+// 继续直接使用MySQLWriter以保留SHOW CREATE TABLE的精确建表语义，此适配器仅供跨方言场景使用
+type mysqlGenericWriter struct {
+	*MySQLWriter
+}
+
+// CreateTableLike 根据标准化列信息建表，覆盖内嵌MySQLWriter基于源库SHOW CREATE TABLE的同名方法
+func (w *mysqlGenericWriter) CreateTableLike(columns []ColumnInfo) error {
+	return w.MySQLWriter.CreateTableLikeColumns(columns)
+}
+
+// logicalType 将各方言原始列类型字符串归一化为通用逻辑类型，用于跨方言建表时翻译目标DDL
+func logicalType(rawType string) string {
+	t := strings.ToLower(rawType)
+	switch {
+	case strings.Contains(t, "int"):
+		return "bigint"
+	case containsAny(t, "decimal", "numeric", "float", "double", "real"):
+		return "decimal"
+	case strings.Contains(t, "bool"):
+		return "bool"
+	case containsAny(t, "date", "time"):
+		return "datetime"
+	case containsAny(t, "text", "blob", "json"):
+		return "text"
+	default:
+		return "string"
+	}
+}
+
+// containsAny 判断字符串是否包含任一关键字（不区分大小写，调用方需先转小写）
+func containsAny(s string, keywords ...string) bool {
+	for _, k := range keywords {
+		if strings.Contains(s, k) {
+			return true
+		}
+	}
+	return false
+}
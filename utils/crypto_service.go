@@ -0,0 +1,311 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"datatrace/config"
+	"datatrace/database"
+	"datatrace/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	rsaKeyBits            = 2048
+	defaultRotationPeriod = 30 * 24 * time.Hour // config.Crypto.RotationPeriodDays未配置时的默认轮换周期
+)
+
+// currentNodeID 本进程所在节点的标识，仅用于标记密钥版本由哪个节点生成，便于审计排查；
+// 密钥本身在集群内所有节点间共享可见，不按节点隔离
+var currentNodeID = func() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return "unknown"
+	}
+	return hostname
+}()
+
+// CryptoService 存储凭据的信封加密服务：每次加密生成随机AES-256数据密钥(DEK)加密明文，
+// 再用当前激活的RSA公钥加密DEK；密文中携带密钥版本号，解密时按版本取出对应RSA私钥。
+// RSA密钥支持动态轮换（见RotateKey），历史版本保留在库中用于解密旧数据，永不删除
+type CryptoService struct {
+	masterKey []byte // 主密钥，来自配置，仅用于加解密落库的RSA私钥本身
+}
+
+// NewCryptoService 创建加密服务，使用配置中的主密钥保护RSA私钥
+func NewCryptoService() *CryptoService {
+	return &CryptoService{masterKey: []byte(config.GlobalConfig.Security.EncryptionKey)}
+}
+
+// Encrypt 信封加密，输出格式为 版本号:base64(RSA加密后的数据密钥):base64(nonce+AES密文)
+func (s *CryptoService) Encrypt(plaintext string) (string, error) {
+	key, err := s.getOrCreateActiveKey()
+	if err != nil {
+		return "", err
+	}
+
+	pubKey, err := parsePublicKey(key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return "", fmt.Errorf("生成数据密钥失败: %w", err)
+	}
+
+	ciphertext, err := aesGCMEncrypt(dek, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	encryptedDEK, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pubKey, dek, nil)
+	if err != nil {
+		return "", fmt.Errorf("加密数据密钥失败: %w", err)
+	}
+
+	return fmt.Sprintf("%d:%s:%s",
+		key.Version,
+		base64.StdEncoding.EncodeToString(encryptedDEK),
+		base64.StdEncoding.EncodeToString(ciphertext),
+	), nil
+}
+
+// Decrypt 解析信封密文中的密钥版本，取出对应版本的RSA私钥解密数据密钥，再解密正文
+func (s *CryptoService) Decrypt(encoded string) (string, error) {
+	parts := strings.SplitN(encoded, ":", 3)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("密文格式无效")
+	}
+
+	version, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("密文版本无效: %w", err)
+	}
+
+	var key models.EncryptionKey
+	if err := database.DB.Where("version = ?", version).First(&key).Error; err != nil {
+		return "", fmt.Errorf("未找到版本 %d 对应的密钥: %w", version, err)
+	}
+
+	privKey, err := s.decryptPrivateKey(key.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	encryptedDEK, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("数据密钥解码失败: %w", err)
+	}
+	dek, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, privKey, encryptedDEK, nil)
+	if err != nil {
+		return "", fmt.Errorf("解密数据密钥失败: %w", err)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("密文解码失败: %w", err)
+	}
+	plaintext, err := aesGCMDecrypt(dek, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("解密失败: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// RotateKey 生成新一代RSA密钥对并设为激活版本，旧版本转为非激活但保留在库中用于解密历史数据
+func (s *CryptoService) RotateKey() error {
+	var current models.EncryptionKey
+	nextVersion := 1
+	if err := database.DB.Order("version desc").First(&current).Error; err == nil {
+		nextVersion = current.Version + 1
+	}
+
+	if err := database.DB.Model(&models.EncryptionKey{}).
+		Where("active = ?", true).
+		Update("active", false).Error; err != nil {
+		return fmt.Errorf("下线旧密钥失败: %w", err)
+	}
+
+	if _, err := s.generateKey(nextVersion); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RotationDue 判断当前激活密钥是否已超过轮换周期，供后台轮换任务周期性检查
+func (s *CryptoService) RotationDue() (bool, error) {
+	key, err := s.getOrCreateActiveKey()
+	if err != nil {
+		return false, err
+	}
+	return time.Since(key.CreatedAt) >= rotationPeriod(), nil
+}
+
+// rotationPeriod 返回配置的轮换周期，未配置时退回defaultRotationPeriod
+func rotationPeriod() time.Duration {
+	if config.GlobalConfig != nil && config.GlobalConfig.Crypto.RotationPeriodDays > 0 {
+		return time.Duration(config.GlobalConfig.Crypto.RotationPeriodDays) * 24 * time.Hour
+	}
+	return defaultRotationPeriod
+}
+
+// KeyInfo 密钥版本的脱敏信息，供GET /crypto/keys展示，不包含公私钥材料
+type KeyInfo struct {
+	Version   int       `json:"version"`
+	Active    bool      `json:"active"`
+	NodeID    string    `json:"node_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListKeyVersions 列出全部密钥版本的脱敏信息，按版本号降序排列
+func (s *CryptoService) ListKeyVersions() ([]KeyInfo, error) {
+	var keys []models.EncryptionKey
+	if err := database.DB.Order("version desc").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("查询密钥列表失败: %w", err)
+	}
+
+	infos := make([]KeyInfo, 0, len(keys))
+	for _, key := range keys {
+		infos = append(infos, KeyInfo{
+			Version:   key.Version,
+			Active:    key.Active,
+			NodeID:    key.NodeID,
+			CreatedAt: key.CreatedAt,
+		})
+	}
+	return infos, nil
+}
+
+// getOrCreateActiveKey 获取当前激活的密钥版本，库中尚无密钥时自动生成首个版本
+func (s *CryptoService) getOrCreateActiveKey() (*models.EncryptionKey, error) {
+	var key models.EncryptionKey
+	err := database.DB.Where("active = ?", true).Order("version desc").First(&key).Error
+	if err == nil {
+		return &key, nil
+	}
+	return s.generateKey(1)
+}
+
+// generateKey 生成一个新的RSA密钥对，私钥用主密钥加密后与公钥一并写入数据库
+func (s *CryptoService) generateKey(version int) (*models.EncryptionKey, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("生成RSA密钥失败: %w", err)
+	}
+
+	pubBytes, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("序列化公钥失败: %w", err)
+	}
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes})
+
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
+	encryptedPriv, err := s.encryptWithMasterKey(privPEM)
+	if err != nil {
+		return nil, fmt.Errorf("加密私钥失败: %w", err)
+	}
+
+	key := &models.EncryptionKey{
+		ID:         uuid.New().String(),
+		Version:    version,
+		PublicKey:  string(pubPEM),
+		PrivateKey: encryptedPriv,
+		Active:     true,
+		NodeID:     currentNodeID,
+	}
+	if err := database.DB.Create(key).Error; err != nil {
+		return nil, fmt.Errorf("保存密钥失败: %w", err)
+	}
+	return key, nil
+}
+
+// encryptWithMasterKey 用主密钥(AES-GCM)加密RSA私钥的PEM编码，用于落库保存
+func (s *CryptoService) encryptWithMasterKey(plaintext []byte) (string, error) {
+	ciphertext, err := aesGCMEncrypt(s.masterKey, plaintext)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// decryptPrivateKey 用主密钥解密库中保存的RSA私钥并解析为可用的私钥对象
+func (s *CryptoService) decryptPrivateKey(encoded string) (*rsa.PrivateKey, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("私钥解码失败: %w", err)
+	}
+	plain, err := aesGCMDecrypt(s.masterKey, data)
+	if err != nil {
+		return nil, fmt.Errorf("私钥解密失败: %w", err)
+	}
+	block, _ := pem.Decode(plain)
+	if block == nil {
+		return nil, fmt.Errorf("私钥PEM解析失败")
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// parsePublicKey 解析PEM编码的RSA公钥
+func parsePublicKey(pemStr string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(pemStr))
+	if block == nil {
+		return nil, fmt.Errorf("公钥PEM解析失败")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析公钥失败: %w", err)
+	}
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("密钥类型不是RSA")
+	}
+	return rsaPub, nil
+}
+
+// aesGCMEncrypt 用给定密钥对明文做AES-GCM加密，返回 nonce+密文
+func aesGCMEncrypt(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("创建AES加密器失败: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("创建GCM模式失败: %w", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("生成nonce失败: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// aesGCMDecrypt 解密aesGCMEncrypt生成的 nonce+密文
+func aesGCMDecrypt(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("创建AES解密器失败: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("创建GCM模式失败: %w", err)
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("密文长度无效")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"testing"
+
+	"datatrace/config"
+	"datatrace/database"
+	"datatrace/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupCryptoTestDB 用按测试名命名的内存SQLite替换database.DB（每个测试各自一份空库），并写入一份固定的32字节主密钥，
+// 测试结束时恢复原值，避免影响同一进程内的其它测试
+func setupCryptoTestDB(t *testing.T) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("打开内存数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(&models.EncryptionKey{}); err != nil {
+		t.Fatalf("迁移表结构失败: %v", err)
+	}
+
+	prevDB := database.DB
+	prevConfig := config.GlobalConfig
+	database.DB = db
+	config.GlobalConfig = &config.Config{
+		Security: config.SecurityConfig{EncryptionKey: "01234567890123456789012345678901"},
+	}
+
+	t.Cleanup(func() {
+		database.DB = prevDB
+		config.GlobalConfig = prevConfig
+	})
+}
+
+// TestCryptoServiceEncryptDecryptRoundTrip 验证首次加解密（自动生成首个密钥版本）能正确往返
+func TestCryptoServiceEncryptDecryptRoundTrip(t *testing.T) {
+	setupCryptoTestDB(t)
+	svc := NewCryptoService()
+
+	encoded, err := svc.Encrypt("hello world")
+	if err != nil {
+		t.Fatalf("Encrypt失败: %v", err)
+	}
+
+	plaintext, err := svc.Decrypt(encoded)
+	if err != nil {
+		t.Fatalf("Decrypt失败: %v", err)
+	}
+	if plaintext != "hello world" {
+		t.Fatalf("解密结果不匹配: 期望 %q, 实际 %q", "hello world", plaintext)
+	}
+}
+
+// TestCryptoServiceRotateKeyCreatesNewActiveVersion 验证RotateKey会下线旧版本并生成新的激活版本
+func TestCryptoServiceRotateKeyCreatesNewActiveVersion(t *testing.T) {
+	setupCryptoTestDB(t)
+	svc := NewCryptoService()
+
+	if _, err := svc.getOrCreateActiveKey(); err != nil {
+		t.Fatalf("初始化首个密钥版本失败: %v", err)
+	}
+
+	if err := svc.RotateKey(); err != nil {
+		t.Fatalf("RotateKey失败: %v", err)
+	}
+
+	keys, err := svc.ListKeyVersions()
+	if err != nil {
+		t.Fatalf("ListKeyVersions失败: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("期望轮换后有2个密钥版本，实际 %d 个", len(keys))
+	}
+
+	var activeCount, v1Active, v2Active bool
+	for _, k := range keys {
+		if k.Active {
+			activeCount = true
+			if k.Version == 2 {
+				v2Active = true
+			}
+			if k.Version == 1 {
+				v1Active = true
+			}
+		}
+	}
+	if !activeCount || !v2Active || v1Active {
+		t.Fatalf("轮换后应只有版本2处于激活状态，实际: %+v", keys)
+	}
+}
+
+// TestCryptoServiceDecryptWithRetiredKey 验证轮换后，用旧版本密钥加密的密文依然可以解密
+// （旧私钥被保留、只是Active置为false，而不是删除）
+func TestCryptoServiceDecryptWithRetiredKey(t *testing.T) {
+	setupCryptoTestDB(t)
+	svc := NewCryptoService()
+
+	encoded, err := svc.Encrypt("secret-before-rotation")
+	if err != nil {
+		t.Fatalf("Encrypt失败: %v", err)
+	}
+
+	if err := svc.RotateKey(); err != nil {
+		t.Fatalf("RotateKey失败: %v", err)
+	}
+
+	// 轮换后新的加密应使用新版本密钥
+	encodedAfter, err := svc.Encrypt("secret-after-rotation")
+	if err != nil {
+		t.Fatalf("Encrypt失败: %v", err)
+	}
+	if encodedAfter[:1] != "2" {
+		t.Fatalf("轮换后应使用版本2加密，实际密文版本前缀为 %q", encodedAfter[:1])
+	}
+
+	plaintext, err := svc.Decrypt(encoded)
+	if err != nil {
+		t.Fatalf("用已退役版本的密钥解密失败: %v", err)
+	}
+	if plaintext != "secret-before-rotation" {
+		t.Fatalf("解密结果不匹配: 期望 %q, 实际 %q", "secret-before-rotation", plaintext)
+	}
+}
+
+// TestCryptoServiceDecryptRefusesWhenPrivateKeyMissing 验证当对应版本的密钥记录不存在
+// （如私钥已被清理或版本号被篡改）时，Decrypt返回错误而不是panic或静默成功
+func TestCryptoServiceDecryptRefusesWhenPrivateKeyMissing(t *testing.T) {
+	setupCryptoTestDB(t)
+	svc := NewCryptoService()
+
+	encoded, err := svc.Encrypt("needs-a-key")
+	if err != nil {
+		t.Fatalf("Encrypt失败: %v", err)
+	}
+
+	if err := database.DB.Where("version = ?", 1).Delete(&models.EncryptionKey{}).Error; err != nil {
+		t.Fatalf("删除密钥记录失败: %v", err)
+	}
+
+	if _, err := svc.Decrypt(encoded); err == nil {
+		t.Fatal("私钥记录缺失时Decrypt应返回错误，实际返回nil")
+	}
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"os"
+
+	"datatrace/config"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Log 全局结构化日志器，InitLogger 之前为空实现，避免未初始化时调用panic
+var Log *zap.Logger = zap.NewNop()
+
+// InitLogger 根据配置初始化全局日志器：按大小/天数滚动写入文件，可选同时输出到控制台
+func InitLogger(cfg config.LogConfig) error {
+	level, err := zapcore.ParseLevel(cfg.Level)
+	if err != nil {
+		level = zapcore.InfoLevel
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+
+	fileWriter := zapcore.AddSync(&lumberjack.Logger{
+		Filename:   cfg.FilePath,
+		MaxSize:    cfg.MaxSizeMB,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAgeDays,
+		Compress:   cfg.Compress,
+	})
+
+	cores := []zapcore.Core{
+		zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), fileWriter, level),
+	}
+	if cfg.Console {
+		consoleEncoderConfig := encoderConfig
+		consoleEncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		cores = append(cores, zapcore.NewCore(zapcore.NewConsoleEncoder(consoleEncoderConfig), zapcore.AddSync(os.Stdout), level))
+	}
+
+	Log = zap.New(zapcore.NewTee(cores...), zap.AddCaller())
+	return nil
+}